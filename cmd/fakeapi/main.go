@@ -0,0 +1,36 @@
+// Command fakeapi serves the PokeAPI fixtures locally, for offline
+// development. Run pokedexcli against it with
+//
+//	pokedexcli -base-url http://localhost:8080/api/v2
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokeapi/fakeapi"
+	"net/http"
+	"os"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "Address to listen on")
+	fixtures := flag.String("fixtures", "", "Directory of fixtures to serve instead of the built-in ones")
+	flag.Parse()
+
+	server := fakeapi.Default()
+	if *fixtures != "" {
+		var err error
+		server, err = fakeapi.New(os.DirFS(*fixtures))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("Serving fake PokeAPI at http://%s%s\n", *addr, fakeapi.BasePath)
+	err := http.ListenAndServe(*addr, server)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
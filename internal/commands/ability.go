@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"os"
+	"strings"
+)
+
+const abilityUsage = "ability [-lang code] <name-or-number>"
+
+func commandAbility(ctx context.Context, context *CliCommandContext) error {
+	flags := flag.NewFlagSet("ability", flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+	language := flags.String("lang", context.Language, "Language code for names and text, e.g. en or de")
+	// Language codes are case sensitive, e.g. ja-Hrkt, so parse as typed
+	err := flags.Parse(context.RawArguments)
+	if err != nil || flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s", abilityUsage)
+	}
+	abilityName := strings.ToLower(flags.Arg(0))
+
+	ability, err := context.Client.GetAbility(ctx, abilityName)
+	var notFound *pokeapi.NotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("no such ability: %s", abilityName)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Println(localized(ability.Names, *language, ability.Name))
+	effect, ok := pokeapi.LocalizedEffect(ability.EffectEntries, *language)
+	if !ok {
+		effect, ok = pokeapi.LocalizedEffect(ability.EffectEntries, pokeapi.DefaultLanguage)
+	}
+	if ok {
+		fmt.Printf("Effect: %s\n", effect.ShortEffect)
+	}
+	fmt.Printf("Introduced in: %s\n", ability.Generation.Name)
+	fmt.Println("Pokémon:")
+	for _, holder := range ability.Pokemon {
+		if holder.IsHidden {
+			fmt.Printf("  - %s (hidden)\n", holder.Pokemon.Name)
+		} else {
+			fmt.Printf("  - %s\n", holder.Pokemon.Name)
+		}
+	}
+	return nil
+}
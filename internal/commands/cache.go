@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokecache"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+func commandCache(ctx context.Context, context *CliCommandContext) error {
+	if len(context.Arguments) < 1 {
+		return fmt.Errorf("cache command expects a subcommand: stats, list, clear, purge <url-prefix>, export <file> or import <file>")
+	}
+	store := context.Client.Store()
+
+	// Only the in-memory Cache keeps statistics, other stores are just listed
+	cache, isCache := store.(*pokecache.Cache)
+
+	switch subcommand := context.Arguments[0]; subcommand {
+	case "stats":
+		if !isCache {
+			fmt.Printf("Stored: %d entries (no statistics for this cache backend)\n", len(store.Keys()))
+			return nil
+		}
+		stats := cache.Stats()
+		lookups := stats.Hits + stats.DiskHits + stats.Misses
+		hitRate := 0.0
+		if lookups > 0 {
+			hitRate = 100 * float64(stats.Hits+stats.DiskHits) / float64(lookups)
+		}
+		fmt.Printf("Lookups: %d (%.1f%% hit rate)\n", lookups, hitRate)
+		fmt.Printf("  - memory hits: %d\n", stats.Hits)
+		fmt.Printf("  - disk hits: %d\n", stats.DiskHits)
+		fmt.Printf("  - misses: %d\n", stats.Misses)
+		fmt.Printf("In memory: %d entries, %d bytes (%d uncompressed)\n", stats.Entries, stats.Bytes, stats.RawBytes)
+		if stats.Entries > 0 {
+			fmt.Printf("  - oldest: %v\n", stats.OldestAge.Round(time.Second))
+			fmt.Printf("  - newest: %v\n", stats.NewestAge.Round(time.Second))
+		}
+		fmt.Printf("Evictions: %d\n", stats.Evictions)
+		fmt.Printf("Expirations: %d\n", stats.Expirations)
+		if stats.DiskEntries >= 0 {
+			fmt.Printf("On disk: %d entries\n", stats.DiskEntries)
+		}
+	case "list":
+		if !isCache {
+			keys := store.Keys()
+			fmt.Printf("%d entries stored:\n", len(keys))
+			for _, key := range keys {
+				fmt.Printf(" - %s\n", key)
+			}
+			return nil
+		}
+		entries := cache.Entries()
+		fmt.Printf("%d entries in memory, most recently used first:\n", len(entries))
+		for _, entry := range entries {
+			fmt.Printf(" - %s (%d bytes, %d uncompressed, age %v)\n", entry.Key, entry.Bytes, entry.RawBytes, entry.Age.Round(time.Second))
+		}
+	case "clear":
+		if isCache {
+			err := cache.Clear()
+			if err != nil {
+				return err
+			}
+		} else {
+			pokecache.PurgeStore(store, "")
+		}
+		fmt.Println("Cache cleared")
+	case "purge":
+		if len(context.RawArguments) != 2 {
+			return fmt.Errorf("cache purge expects 1 argument, the URL prefix")
+		}
+		// As typed, since URLs are case sensitive
+		prefix := context.RawArguments[1]
+		var removed int
+		if isCache {
+			var err error
+			removed, err = cache.Purge(prefix)
+			if err != nil {
+				return err
+			}
+		} else {
+			removed = pokecache.PurgeStore(store, prefix)
+		}
+		fmt.Printf("Purged %d entries\n", removed)
+	case "export":
+		if len(context.RawArguments) != 2 {
+			return fmt.Errorf("cache export expects 1 argument, the archive file")
+		}
+		return exportCache(store, context.RawArguments[1])
+	case "import":
+		if len(context.RawArguments) != 2 {
+			return fmt.Errorf("cache import expects 1 argument, the archive file")
+		}
+		return importCache(store, context.RawArguments[1])
+	default:
+		return fmt.Errorf("unknown cache subcommand: %s", subcommand)
+	}
+
+	return nil
+}
+
+// Archives whose name ends in .gz are gzipped
+func exportCache(store pokecache.Store, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var writer io.Writer = file
+	var gzipWriter *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		gzipWriter = gzip.NewWriter(file)
+		writer = gzipWriter
+	}
+
+	exported, err := pokecache.Export(store, writer)
+	if err != nil {
+		return err
+	}
+	if gzipWriter != nil {
+		err = gzipWriter.Close()
+		if err != nil {
+			return err
+		}
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d entries to %s\n", exported, path)
+	return nil
+}
+
+func importCache(store pokecache.Store, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	imported, err := pokecache.Import(store, reader)
+	if err != nil {
+		return fmt.Errorf("import stopped after %d entries: %w", imported, err)
+	}
+
+	fmt.Printf("Imported %d entries from %s\n", imported, path)
+	return nil
+}
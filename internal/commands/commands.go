@@ -1,11 +1,15 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/venzy/pokedexcli/internal/pokeapi"
 	"math"
 	"math/rand"
 	"os"
+	"sort"
+	"strconv"
 	"sync"
 )
 
@@ -13,22 +17,36 @@ const debug = false
 
 type CliCommandContext struct {
 	Arguments []string
+	// As typed, for arguments where case matters such as file paths
+	RawArguments []string
 	Previous *string
 	Next *string
 	Caught map[string]*pokeapi.PokemonDetail
+	Client *pokeapi.Client
+	// Returns a number in [0.0, 1.0) for catch attempts, replaceable so
+	// tests can be deterministic
+	Roll func() float64
+	// Preferred language code for names and text, e.g. "en" or "ja"
+	Language string
+	// Preferred game version for Pokédex entries, empty for the latest
+	GameVersion string
 }
 
-func NewContext() *CliCommandContext {
+func NewContext(client *pokeapi.Client) *CliCommandContext {
 	context := CliCommandContext{}
+	context.Client = client
 	context.Arguments = []string{}
+	context.RawArguments = []string{}
 	context.Caught = map[string]*pokeapi.PokemonDetail{}
+	context.Roll = rand.Float64
+	context.Language = pokeapi.DefaultLanguage
 	return &context
 }
 
 type CliCommand struct {
 	Name string
 	Description string
-	Callback func(context *CliCommandContext) error
+	Callback func(ctx context.Context, context *CliCommandContext) error
 }
 
 type Registry map[string]CliCommand
@@ -60,17 +78,17 @@ func GetRegistry() *Registry {
 			},
 			"explore": {
 				Name: "explore",
-				Description: "Return a list of all Pokémon in a given location",
+				Description: "Return a list of all Pokémon in a given location, by name or ID",
 				Callback: commandExplore,
 			},
 			"catch": {
 				Name: "catch",
-				Description: "Attempt to catch a given Pokémon",
+				Description: "Attempt to catch a given Pokémon, by name or Dex number",
 				Callback: commandCatch,
 			},
 			"inspect": {
 				Name: "inspect",
-				Description: "Inspect a given Pokémon you've already caught",
+				Description: "Inspect a given Pokémon you've already caught, by name or Dex number",
 				Callback: commandInspect,
 			},
 			"pokedex": {
@@ -78,18 +96,53 @@ func GetRegistry() *Registry {
 				Description: "List the Pokémon you've already caught",
 				Callback: commandPokedex,
 			},
+			"species": {
+				Name: "species",
+				Description: "Show the Pokédex entry for a Pokémon species: " + speciesUsage,
+				Callback: commandSpecies,
+			},
+			"evolutions": {
+				Name: "evolutions",
+				Description: "Show how a Pokémon's family evolves, by name or Dex number",
+				Callback: commandEvolutions,
+			},
+			"matchup": {
+				Name: "matchup",
+				Description: "Show type effectiveness for a type, or a Pokémon's weaknesses and resistances",
+				Callback: commandMatchup,
+			},
+			"moves": {
+				Name: "moves",
+				Description: "List the moves a Pokémon learns: " + movesUsage,
+				Callback: commandMoves,
+			},
+			"ability": {
+				Name: "ability",
+				Description: "Describe an ability and list the Pokémon that have it: " + abilityUsage,
+				Callback: commandAbility,
+			},
+			"cache": {
+				Name: "cache",
+				Description: "Inspect the response cache: cache stats | list | clear | purge <url-prefix> | export <file> | import <file>",
+				Callback: commandCache,
+			},
+			"prefetch": {
+				Name: "prefetch",
+				Description: "Download resources into the cache for offline use: " + prefetchUsage,
+				Callback: commandPrefetch,
+			},
 		}
 	})
 	return registryInstance
 }
 
-func commandExit(context *CliCommandContext) error {
+func commandExit(ctx context.Context, context *CliCommandContext) error {
 	fmt.Println("Closing the Pokedex... Goodbye!")
 	os.Exit(0)
 	return nil
 }
 
-func commandHelp(context *CliCommandContext) error {
+func commandHelp(ctx context.Context, context *CliCommandContext) error {
 	fmt.Println("Welcome to the Pokedex!")
 	fmt.Println("Usage:")
 	fmt.Println()
@@ -99,7 +152,7 @@ func commandHelp(context *CliCommandContext) error {
 	return nil
 }
 
-func commandMapNext(context *CliCommandContext) error {
+func commandMapNext(ctx context.Context, context *CliCommandContext) error {
 	if context.Next == nil {
 		if context.Previous != nil {
 			fmt.Println("You're on the last page")
@@ -107,7 +160,7 @@ func commandMapNext(context *CliCommandContext) error {
 		}
 	}
 
-	data, err := pokeapi.GetLocationAreas(context.Next)
+	data, err := context.Client.GetLocationAreas(ctx, context.Next)
 	if err != nil {
 		return err
 	}
@@ -122,7 +175,7 @@ func commandMapNext(context *CliCommandContext) error {
 	return nil
 }
 
-func commandMapBack(context *CliCommandContext) error {
+func commandMapBack(ctx context.Context, context *CliCommandContext) error {
 	if context.Previous == nil {
 		if context.Next == nil {
 			fmt.Println("Must use 'map' command at least once before 'mapb'")
@@ -132,7 +185,7 @@ func commandMapBack(context *CliCommandContext) error {
 		return nil
 	}
 
-	data, err := pokeapi.GetLocationAreas(context.Previous)
+	data, err := context.Client.GetLocationAreas(ctx, context.Previous)
 	if err != nil {
 		return err
 	}
@@ -147,18 +200,21 @@ func commandMapBack(context *CliCommandContext) error {
 	return nil
 }
 
-func commandExplore(context *CliCommandContext) error {
+func commandExplore(ctx context.Context, context *CliCommandContext) error {
 	if len(context.Arguments) != 1 {
-		return fmt.Errorf("explore command expects 1 argument, the area name")
+		return fmt.Errorf("explore command expects 1 argument, the area name or ID")
 	}
 	areaName := context.Arguments[0]
 
-	detail, err := pokeapi.GetLocationAreaDetail(areaName)
-	if err != nil {
+	detail, err := context.Client.GetLocationAreaDetail(ctx, areaName)
+	var notFound *pokeapi.NotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("no such area: %s", areaName)
+	} else if err != nil {
 		return err
 	}
 
-	fmt.Printf("Exploring %s...\n", areaName)
+	fmt.Printf("Exploring %s...\n", detail.Name)
 	for _, encounter := range detail.PokemonEncounters {
 		fmt.Printf(" - %s\n", encounter.Pokemon.Name)
 	}
@@ -166,21 +222,26 @@ func commandExplore(context *CliCommandContext) error {
 	return nil
 }
 
-func commandCatch(context *CliCommandContext) error {
+func commandCatch(ctx context.Context, context *CliCommandContext) error {
 	if len(context.Arguments) != 1 {
-		return fmt.Errorf("catch command expects 1 argument, the Pokémon name")
+		return fmt.Errorf("catch command expects 1 argument, the Pokémon name or Dex number")
 	}
-	pokemonName := context.Arguments[0]
+	nameOrID := context.Arguments[0]
 
-	if _, ok := context.Caught[pokemonName]; ok {
-		fmt.Printf("%s already caught!\n", pokemonName)
+	if caught, ok := findCaught(context, nameOrID); ok {
+		fmt.Printf("%s already caught!\n", caught.Name)
 		return nil
 	}
 
-	detail, err := pokeapi.GetPokemonDetail(pokemonName)
-	if err != nil {
+	detail, err := context.Client.GetPokemonDetail(ctx, nameOrID)
+	var notFound *pokeapi.NotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("no such Pokémon: %s", nameOrID)
+	} else if err != nil {
 		return err
 	}
+	// Caught is keyed on the canonical name, whatever the user typed
+	pokemonName := detail.Name
 
 	fmt.Printf("Throwing a Pokeball at %s...\n", pokemonName)
 
@@ -196,7 +257,7 @@ func commandCatch(context *CliCommandContext) error {
 	catchChance := math.Max(0.2, scaledDifficulty)
 
 	// Roll for Pokemon to escape
-	roll := rand.Float64()
+	roll := context.Roll()
 
 	if debug {
 		fmt.Printf("DEBUG: escape roll: %v, catchChance: %v\n", roll, catchChance)
@@ -215,13 +276,12 @@ func commandCatch(context *CliCommandContext) error {
 	return nil
 }
 
-func commandInspect(context *CliCommandContext) error {
+func commandInspect(ctx context.Context, context *CliCommandContext) error {
 	if len(context.Arguments) != 1 {
-		return fmt.Errorf("inspect command expects 1 argument, the Pokémon name")
+		return fmt.Errorf("inspect command expects 1 argument, the Pokémon name or Dex number")
 	}
-	pokemonName := context.Arguments[0]
 
-	detail, ok := context.Caught[pokemonName]
+	detail, ok := findCaught(context, context.Arguments[0])
 	if !ok {
 		return fmt.Errorf("you have not caught that pokemon")
 	}
@@ -237,13 +297,43 @@ func commandInspect(context *CliCommandContext) error {
 	for _, typeInfo := range detail.Types {
 		fmt.Printf("  - %s\n", typeInfo.Type.Name)
 	}
+	fmt.Println("Abilities:")
+	for _, abilityInfo := range detail.Abilities {
+		if abilityInfo.IsHidden {
+			fmt.Printf("  - %s (hidden)\n", abilityInfo.Ability.Name)
+		} else {
+			fmt.Printf("  - %s\n", abilityInfo.Ability.Name)
+		}
+	}
 
 	return nil
 }
 
-func commandPokedex(context *CliCommandContext) error {
+// Look up a caught Pokémon by name or by Dex number
+func findCaught(context *CliCommandContext, nameOrID string) (*pokeapi.PokemonDetail, bool) {
+	if detail, ok := context.Caught[nameOrID]; ok {
+		return detail, true
+	}
+	id, err := strconv.Atoi(nameOrID)
+	if err != nil {
+		return nil, false
+	}
+	for _, detail := range context.Caught {
+		if detail.ID == id {
+			return detail, true
+		}
+	}
+	return nil, false
+}
+
+func commandPokedex(ctx context.Context, context *CliCommandContext) error {
 	fmt.Println("Your Pokedex:")
+	names := make([]string, 0, len(context.Caught))
 	for name := range context.Caught {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
 		fmt.Printf(" - %s\n", name)
 	}
 	return nil
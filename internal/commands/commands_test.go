@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"github.com/venzy/pokedexcli/internal/pokecache"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Re-record the responses from the real PokeAPI, along with the golden
+// transcripts, with
+//
+//	go test ./internal/commands -record
+var record = flag.Bool("record", false, "Re-record responses from PokeAPI and rewrite golden output")
+
+const responsesDir = "testdata/responses"
+
+// Run each line as if typed at the prompt, replaying recorded responses, and
+// compare the transcript with testdata/<test name>.golden. Catch attempts
+// always roll the given value.
+func runScript(t *testing.T, roll float64, lines ...string) {
+	t.Helper()
+
+	options := []pokeapi.Option{
+		pokeapi.WithHTTPClient(&http.Client{Transport: &pokeapi.ReplayTransport{Dir: responsesDir, Record: *record}}),
+		pokeapi.WithRetryPolicy(pokeapi.RetryPolicy{MaxAttempts: 1}),
+	}
+	if !*record {
+		// Only go easy on the real PokeAPI
+		options = append(options, pokeapi.WithRateLimit(0, 0))
+	}
+	client := pokeapi.NewClient(options...)
+	defer client.Close()
+	commandContext := NewContext(client)
+	commandContext.Roll = func() float64 { return roll }
+
+	transcript := captureStdout(t, func() {
+		for _, line := range lines {
+			fmt.Printf("> %s\n", line)
+			tokens := strings.Fields(strings.ToLower(line))
+			commandContext.Arguments = tokens[1:]
+			commandContext.RawArguments = strings.Fields(line)[1:]
+			command, ok := (*GetRegistry())[tokens[0]]
+			if !ok {
+				t.Fatalf("unknown command in script: %s", tokens[0])
+			}
+			err := command.Callback(context.Background(), commandContext)
+			if err != nil {
+				fmt.Println(err)
+			}
+		}
+	})
+
+	golden := filepath.Join("testdata", t.Name()+".golden")
+	if *record {
+		err := os.WriteFile(golden, transcript, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v (run with -record to create it)", err)
+	}
+	if !bytes.Equal(transcript, expected) {
+		t.Errorf("output differs from %s\n--- got:\n%s--- expected:\n%s", golden, transcript, expected)
+	}
+}
+
+// Commands print straight to stdout, so swap it for a pipe while fn runs
+func captureStdout(t *testing.T, fn func()) []byte {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	output := make(chan []byte)
+	go func() {
+		captured, _ := io.ReadAll(reader)
+		output <- captured
+	}()
+
+	defer func() {
+		os.Stdout = stdout
+	}()
+	fn()
+	writer.Close()
+	return <-output
+}
+
+func TestMap(t *testing.T) {
+	runScript(t, 0,
+		"mapb",
+		"map",
+		"map",
+		"map",
+		"mapb",
+		"mapb",
+	)
+}
+
+func TestExplore(t *testing.T) {
+	runScript(t, 0,
+		"explore canalave-city-area",
+		"explore nowhere",
+		"explore",
+	)
+}
+
+func TestCatchAndInspect(t *testing.T) {
+	runScript(t, 0,
+		"inspect pikachu",
+		"catch pikachu",
+		"catch pikachu",
+		"inspect pikachu",
+		"catch missingno",
+		"pokedex",
+	)
+}
+
+func TestCatchEscapes(t *testing.T) {
+	runScript(t, 0.99,
+		"catch magikarp",
+		"inspect magikarp",
+	)
+}
+
+func TestByID(t *testing.T) {
+	runScript(t, 0,
+		"explore 1",
+		"catch 25",
+		"catch pikachu",
+		"inspect 25",
+		"inspect pikachu",
+		"catch 129",
+		"inspect magikarp",
+		"pokedex",
+	)
+}
+
+func TestSpecies(t *testing.T) {
+	runScript(t, 0,
+		"species pikachu",
+		"species -lang ja 25",
+		"species -lang ja-Hrkt Pikachu",
+		"species -version red bulbasaur",
+		"species -version Red bulbasaur",
+		"species -version gold magikarp",
+		"species missingno",
+		"species",
+	)
+}
+
+func TestEvolutions(t *testing.T) {
+	runScript(t, 0,
+		"evolutions bulbasaur",
+		"evolutions 25",
+		"evolutions eevee",
+		"evolutions abra",
+		"evolutions missingno",
+	)
+}
+
+func TestMatchup(t *testing.T) {
+	runScript(t, 0,
+		"matchup fire",
+		"matchup normal",
+		"matchup pikachu",
+		"matchup 1",
+		"matchup tentacool",
+		"matchup stellar",
+		"matchup missingno",
+	)
+}
+
+func TestMoves(t *testing.T) {
+	runScript(t, 0,
+		"moves pikachu",
+		"moves -version-group red-blue pikachu",
+		"moves -version-group red-blue -method all -details 25",
+		"moves -version-group red-blue -method machine tentacool",
+		"moves -version-group red-blue -method egg magikarp",
+		"moves -version-group red-blue pikachu thunderbolt",
+		"moves -version-group red-blue charmander quick-attack",
+		"moves -version-group red-blue pikachu quick-attack",
+		"moves -method hm pikachu",
+		"moves pikachu nosuchmove",
+		"moves pikachu-rock-star",
+		"moves",
+	)
+}
+
+func TestAbility(t *testing.T) {
+	runScript(t, 0,
+		"ability static",
+		"ability -lang de static",
+		"ability -lang de 31",
+		"ability -lang ja-Hrkt Static",
+		"ability rain-dish",
+		"ability nosuchability",
+		"ability",
+	)
+}
+
+func TestCachePurgeKeepsCase(t *testing.T) {
+	cache := pokecache.NewCache(time.Hour)
+	defer cache.Close()
+	client := pokeapi.NewClient(pokeapi.WithCache(cache))
+	defer client.Close()
+	cache.Add("https://example.com/Pokemon/Pikachu", []byte("pikachu"))
+	cache.Add("https://example.com/pokemon/pikachu", []byte("pikachu"))
+
+	commandContext := NewContext(client)
+	commandContext.Arguments = []string{"purge", "https://example.com/pokemon/"}
+	commandContext.RawArguments = []string{"purge", "https://example.com/Pokemon/"}
+	output := captureStdout(t, func() {
+		err := commandCache(context.Background(), commandContext)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	if string(output) != "Purged 1 entries\n" {
+		t.Errorf("unexpected output: %q", output)
+	}
+	if keys := cache.Keys(); len(keys) != 1 || keys[0] != "https://example.com/pokemon/pikachu" {
+		t.Errorf("expected only the lowercase key left, got %v", keys)
+	}
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"strings"
+)
+
+func commandEvolutions(ctx context.Context, context *CliCommandContext) error {
+	if len(context.Arguments) != 1 {
+		return fmt.Errorf("evolutions command expects 1 argument, the Pokémon name or Dex number")
+	}
+	nameOrID := context.Arguments[0]
+
+	species, err := getSpecies(ctx, context.Client, nameOrID)
+	var notFound *pokeapi.NotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("no such Pokémon: %s", nameOrID)
+	} else if err != nil {
+		return err
+	}
+	chain, err := context.Client.GetSpeciesEvolutionChain(ctx, species)
+	if err != nil {
+		return err
+	}
+
+	if len(chain.Chain.EvolvesTo) == 0 {
+		fmt.Printf("%s does not evolve\n", species.Name)
+		return nil
+	}
+	printChainLink(&chain.Chain, "", "")
+	return nil
+}
+
+// Print link as a line of an ASCII tree, then its evolutions indented beneath
+// it. linePrefix leads this link's line and childPrefix its children's.
+func printChainLink(link *pokeapi.ChainLink, linePrefix string, childPrefix string) {
+	line := link.Species.Name
+	if link.IsBaby {
+		line += " (baby)"
+	}
+	if len(link.EvolutionDetails) > 0 {
+		ways := make([]string, 0, len(link.EvolutionDetails))
+		for _, detail := range link.EvolutionDetails {
+			if len(link.EvolutionDetails) > 1 {
+				ways = append(ways, "("+detail.String()+")")
+			} else {
+				ways = append(ways, detail.String())
+			}
+		}
+		line += ": " + strings.Join(ways, " or ")
+	}
+	fmt.Println(linePrefix + line)
+
+	for i := range link.EvolvesTo {
+		if i == len(link.EvolvesTo)-1 {
+			printChainLink(&link.EvolvesTo[i], childPrefix+"`-- ", childPrefix+"    ")
+		} else {
+			printChainLink(&link.EvolvesTo[i], childPrefix+"|-- ", childPrefix+"|   ")
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func commandMatchup(ctx context.Context, context *CliCommandContext) error {
+	if len(context.Arguments) != 1 {
+		return fmt.Errorf("matchup command expects 1 argument, a type or a Pokémon name or Dex number")
+	}
+	typeOrPokemon := context.Arguments[0]
+
+	chart, err := context.Client.GetTypeChart(ctx)
+	if err != nil {
+		return err
+	}
+
+	var notFound *pokeapi.NotFoundError
+	// Types have numeric IDs too, but numbers are far more likely to be Dex numbers
+	if _, err := strconv.Atoi(typeOrPokemon); err != nil {
+		t, err := context.Client.GetType(ctx, typeOrPokemon)
+		if err == nil {
+			fmt.Printf("%s attacking:\n", t.Name)
+			printMultipliers(chart.Attacking(t.Name), map[float64]string{
+				2:   "Super effective",
+				0.5: "Not very effective",
+				0:   "No effect",
+			})
+			fmt.Printf("%s defending:\n", t.Name)
+			printMultipliers(chart.Defending(t.Name), defendingLabels)
+			return nil
+		} else if !errors.As(err, &notFound) {
+			return err
+		}
+	}
+
+	detail, err := context.Client.GetPokemonDetail(ctx, typeOrPokemon)
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("no such type or Pokémon: %s", typeOrPokemon)
+	} else if err != nil {
+		return err
+	}
+	types := make([]string, 0, len(detail.Types))
+	for _, typeInfo := range detail.Types {
+		types = append(types, typeInfo.Type.Name)
+	}
+	fmt.Printf("%s (%s) defending:\n", detail.Name, strings.Join(types, "/"))
+	printMultipliers(chart.Defending(types...), defendingLabels)
+	return nil
+}
+
+var defendingLabels = map[float64]string{
+	4:    "Weak",
+	2:    "Weak",
+	0.5:  "Resists",
+	0.25: "Resists",
+	0:    "Immune",
+}
+
+// Print the types with each multiplier other than 1x, strongest first
+func printMultipliers(multipliers map[string]float64, labels map[float64]string) {
+	byMultiplier := map[float64][]string{}
+	for typeName, multiplier := range multipliers {
+		if multiplier != 1 {
+			byMultiplier[multiplier] = append(byMultiplier[multiplier], typeName)
+		}
+	}
+	if len(byMultiplier) == 0 {
+		fmt.Println("  Neutral against everything")
+		return
+	}
+
+	ordered := make([]float64, 0, len(byMultiplier))
+	for multiplier := range byMultiplier {
+		ordered = append(ordered, multiplier)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(ordered)))
+	for _, multiplier := range ordered {
+		typeNames := byMultiplier[multiplier]
+		sort.Strings(typeNames)
+		fmt.Printf("  %s (%gx): %s\n", labels[multiplier], multiplier, strings.Join(typeNames, ", "))
+	}
+}
@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const movesUsage = "moves [-version-group name] [-method level-up|machine|egg|tutor|all] [-details] <name-or-number> [move]"
+
+var learnMethods = []string{"level-up", "machine", "egg", "tutor", "all"}
+
+// One way a Pokémon learns a move in a version group
+type learntMove struct {
+	name   string
+	method string
+	// Only meaningful for level-up
+	level int
+}
+
+func commandMoves(ctx context.Context, context *CliCommandContext) error {
+	flags := flag.NewFlagSet("moves", flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+	versionGroup := flags.String("version-group", "", "Version group to list moves for, e.g. red-blue, empty for the latest")
+	method := flags.String("method", "level-up", "How the moves are learnt: "+strings.Join(learnMethods, ", "))
+	details := flags.Bool("details", false, "Look up the type, power, accuracy and PP of each move")
+	err := flags.Parse(context.Arguments)
+	if err != nil || flags.NArg() < 1 || flags.NArg() > 2 {
+		return fmt.Errorf("usage: %s", movesUsage)
+	}
+	if !slices.Contains(learnMethods, *method) {
+		return fmt.Errorf("unknown learn method %s, expected one of %s", *method, strings.Join(learnMethods, ", "))
+	}
+	nameOrID := flags.Arg(0)
+
+	detail, err := context.Client.GetPokemonDetail(ctx, nameOrID)
+	var notFound *pokeapi.NotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("no such Pokémon: %s", nameOrID)
+	} else if err != nil {
+		return err
+	}
+	group := *versionGroup
+	if group == "" {
+		group, err = latestVersionGroup(ctx, context.Client, detail)
+		if err != nil {
+			return err
+		}
+		if group == "" {
+			fmt.Printf("%s learns no moves in any game\n", detail.Name)
+			return nil
+		}
+	}
+
+	if flags.NArg() == 2 {
+		return printMove(ctx, context, detail, group, flags.Arg(1))
+	}
+
+	learnset := learnsetFor(detail, group, *method)
+	if len(learnset) == 0 {
+		fmt.Printf("%s learns no moves by %s in %s\n", detail.Name, *method, group)
+		return nil
+	}
+
+	if *method == "all" {
+		fmt.Printf("%s learns in %s:\n", detail.Name, group)
+	} else {
+		fmt.Printf("%s learns by %s in %s:\n", detail.Name, *method, group)
+	}
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, learnt := range learnset {
+		how := learnt.method
+		if learnt.method == "level-up" {
+			how = fmt.Sprintf("Lv %d", learnt.level)
+		}
+		if !*details {
+			fmt.Fprintf(table, "  %s\t%s\n", how, learnt.name)
+			continue
+		}
+		move, err := context.Client.GetMove(ctx, learnt.name)
+		if err != nil {
+			table.Flush()
+			return err
+		}
+		fmt.Fprintf(table, "  %s\t%s\t%s\t%s\tpower %s\taccuracy %s\tpp %s\n", how, learnt.name,
+			move.Type.Name, move.DamageClass.Name, optionalInt(move.Power), optionalInt(move.Accuracy), optionalInt(move.PP))
+	}
+	return table.Flush()
+}
+
+// Print everything about one move, and how the Pokémon learns it
+func printMove(ctx context.Context, context *CliCommandContext, detail *pokeapi.PokemonDetail, group string, moveName string) error {
+	move, err := context.Client.GetMove(ctx, moveName)
+	var notFound *pokeapi.NotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("no such move: %s", moveName)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", localized(move.Names, context.Language, move.Name))
+	fmt.Printf("Type: %s\n", move.Type.Name)
+	fmt.Printf("Class: %s\n", move.DamageClass.Name)
+	fmt.Printf("Power: %s\n", optionalInt(move.Power))
+	fmt.Printf("Accuracy: %s\n", optionalInt(move.Accuracy))
+	fmt.Printf("PP: %s\n", optionalInt(move.PP))
+	if move.Priority != 0 {
+		fmt.Printf("Priority: %+d\n", move.Priority)
+	}
+	effect, ok := move.ShortEffect(context.Language)
+	if !ok {
+		effect, ok = move.ShortEffect(pokeapi.DefaultLanguage)
+	}
+	if ok {
+		fmt.Printf("Effect: %s\n", effect)
+	}
+
+	var ways []string
+	for _, learnt := range learnsetFor(detail, group, "all") {
+		if learnt.name != move.Name {
+			continue
+		}
+		if learnt.method == "level-up" {
+			ways = append(ways, fmt.Sprintf("at level %d", learnt.level))
+		} else {
+			ways = append(ways, "by "+learnt.method)
+		}
+	}
+	if len(ways) == 0 {
+		fmt.Printf("%s cannot learn it in %s\n", detail.Name, group)
+	} else {
+		fmt.Printf("%s learns it %s in %s\n", detail.Name, strings.Join(ways, " or "), group)
+	}
+	return nil
+}
+
+// The moves learnt in group by method (or any method for "all"), level-up
+// moves first in level order and then the rest by name
+func learnsetFor(detail *pokeapi.PokemonDetail, group string, method string) []learntMove {
+	var learnset []learntMove
+	for _, move := range detail.Moves {
+		for _, details := range move.VersionGroupDetails {
+			if details.VersionGroup.Name != group {
+				continue
+			}
+			if method != "all" && details.MoveLearnMethod.Name != method {
+				continue
+			}
+			learnset = append(learnset, learntMove{move.Move.Name, details.MoveLearnMethod.Name, details.LevelLearnedAt})
+		}
+	}
+	sort.SliceStable(learnset, func(i, j int) bool {
+		a, b := learnset[i], learnset[j]
+		aLevel, bLevel := a.method == "level-up", b.method == "level-up"
+		if aLevel != bLevel {
+			return aLevel
+		}
+		if aLevel && a.level != b.level {
+			return a.level < b.level
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		// Moves learnt at the same level stay in PokeAPI's order
+		return !aLevel && a.name < b.name
+	})
+	return learnset
+}
+
+// The most recently released version group the Pokémon learns any moves in,
+// or empty if it learns none at all
+func latestVersionGroup(ctx context.Context, client *pokeapi.Client, detail *pokeapi.PokemonDetail) (string, error) {
+	seen := map[string]bool{}
+	var latest *pokeapi.VersionGroup
+	for _, move := range detail.Moves {
+		for _, details := range move.VersionGroupDetails {
+			name := details.VersionGroup.Name
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			group, err := client.GetVersionGroup(ctx, name)
+			if err != nil {
+				return "", err
+			}
+			if latest == nil || group.Order > latest.Order {
+				latest = group
+			}
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return latest.Name, nil
+}
+
+func optionalInt(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprint(*value)
+}
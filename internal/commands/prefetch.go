@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/prefetch"
+	"os"
+)
+
+const prefetchUsage = "prefetch [-workers n] all | areas | pokemon | generation <name-or-number> | region <name>"
+
+func commandPrefetch(ctx context.Context, context *CliCommandContext) error {
+	flags := flag.NewFlagSet("prefetch", flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+	workers := flags.Int("workers", prefetch.DefaultWorkers, "How many resources to fetch at once")
+	err := flags.Parse(context.Arguments)
+	if err != nil {
+		return fmt.Errorf("usage: %s", prefetchUsage)
+	}
+	args := flags.Args()
+	if len(args) < 1 {
+		return fmt.Errorf("usage: %s", prefetchUsage)
+	}
+
+	prefetcher := prefetch.Prefetcher{
+		Client:   context.Client,
+		Workers:  *workers,
+		Progress: os.Stdout,
+	}
+
+	switch target := args[0]; {
+	case target == "all" && len(args) == 1:
+		err = prefetcher.All(ctx)
+	case target == "areas" && len(args) == 1:
+		err = prefetcher.Areas(ctx)
+	case target == "pokemon" && len(args) == 1:
+		err = prefetcher.Pokemon(ctx)
+	case target == "generation" && len(args) == 2:
+		err = prefetcher.Generation(ctx, args[1])
+	case target == "region" && len(args) == 2:
+		err = prefetcher.Region(ctx, args[1])
+	default:
+		return fmt.Errorf("usage: %s", prefetchUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Prefetch complete")
+	return nil
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"os"
+	"strings"
+)
+
+const speciesUsage = "species [-lang code] [-version game] <name-or-number>"
+
+func commandSpecies(ctx context.Context, context *CliCommandContext) error {
+	flags := flag.NewFlagSet("species", flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+	language := flags.String("lang", context.Language, "Language code for names and text, e.g. en or ja")
+	version := flags.String("version", context.GameVersion, "Game version to show the Pokédex entry from, e.g. red, empty for the latest")
+	// Language codes are case sensitive, e.g. ja-Hrkt, so parse as typed
+	err := flags.Parse(context.RawArguments)
+	if err != nil || flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s", speciesUsage)
+	}
+	nameOrID := strings.ToLower(flags.Arg(0))
+	// Unlike language codes, version names are all lowercase
+	*version = strings.ToLower(*version)
+
+	species, err := getSpecies(ctx, context.Client, nameOrID)
+	var notFound *pokeapi.NotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("no such Pokémon: %s", nameOrID)
+	} else if err != nil {
+		return err
+	}
+
+	name := localized(species.Names, *language, species.Name)
+	genus, ok := species.LocalizedGenus(*language)
+	if !ok {
+		genus, _ = species.LocalizedGenus(pokeapi.DefaultLanguage)
+	}
+	fmt.Printf("#%d %s (%s)\n", species.ID, name, genus)
+
+	entry, ok := pokeapi.LocalizedFlavorText(species.FlavorTextEntries, *language, *version)
+	if !ok {
+		entry, ok = pokeapi.LocalizedFlavorText(species.FlavorTextEntries, pokeapi.DefaultLanguage, *version)
+	}
+	if ok {
+		fmt.Printf("%s (%s)\n", pokeapi.CleanFlavorText(entry.FlavorText), entry.Version.Name)
+	} else if *version != "" {
+		fmt.Printf("No Pokédex entry from %s\n", *version)
+	}
+
+	switch {
+	case species.IsLegendary:
+		fmt.Println("Legendary Pokémon")
+	case species.IsMythical:
+		fmt.Println("Mythical Pokémon")
+	case species.IsBaby:
+		fmt.Println("Baby Pokémon")
+	}
+	fmt.Printf("Capture rate: %d\n", species.CaptureRate)
+	fmt.Printf("Base happiness: %d\n", species.BaseHappiness)
+	fmt.Printf("Growth rate: %s\n", species.GrowthRate.Name)
+	eggGroups := make([]string, 0, len(species.EggGroups))
+	for _, group := range species.EggGroups {
+		eggGroups = append(eggGroups, group.Name)
+	}
+	fmt.Printf("Egg groups: %s\n", strings.Join(eggGroups, ", "))
+	varieties := make([]string, 0, len(species.Varieties))
+	for _, variety := range species.Varieties {
+		if variety.IsDefault {
+			varieties = append(varieties, variety.Pokemon.Name+" (default)")
+		} else {
+			varieties = append(varieties, variety.Pokemon.Name)
+		}
+	}
+	fmt.Printf("Varieties: %s\n", strings.Join(varieties, ", "))
+
+	return nil
+}
+
+// Species are usually named after their default Pokémon, but not always
+// (e.g. the deoxys species has deoxys-normal) so fall back to following the
+// Pokémon's species reference
+func getSpecies(ctx context.Context, client *pokeapi.Client, nameOrID string) (*pokeapi.PokemonSpecies, error) {
+	species, err := client.GetPokemonSpecies(ctx, nameOrID)
+	var notFound *pokeapi.NotFoundError
+	if !errors.As(err, &notFound) {
+		return species, err
+	}
+	detail, detailErr := client.GetPokemonDetail(ctx, nameOrID)
+	if errors.As(detailErr, &notFound) {
+		// Report the original miss rather than the fallback's
+		return nil, err
+	} else if detailErr != nil {
+		return nil, detailErr
+	}
+	return client.GetPokemonSpecies(ctx, detail.Species.Name)
+}
+
+// The name in the given language, falling back to the default language and
+// then to fallback, typically the resource's API name
+func localized(names []pokeapi.Name, language string, fallback string) string {
+	if name, ok := pokeapi.LocalizedName(names, language); ok {
+		return name
+	}
+	if name, ok := pokeapi.LocalizedName(names, pokeapi.DefaultLanguage); ok {
+		return name
+	}
+	return fallback
+}
@@ -0,0 +1,17 @@
+package pokeapi
+
+// Ability is a passive effect a Pokémon has in battle. Each Pokémon has one
+// of a few abilities, some of which are hidden (only found by special means).
+type Ability struct {
+	ID            int              `json:"id"`
+	Name          string           `json:"name"`
+	Names         []Name           `json:"names"`
+	IsMainSeries  bool             `json:"is_main_series"`
+	Generation    NamedAPIResource `json:"generation"`
+	EffectEntries []VerboseEffect  `json:"effect_entries"`
+	Pokemon       []struct {
+		IsHidden bool             `json:"is_hidden"`
+		Slot     int              `json:"slot"`
+		Pokemon  NamedAPIResource `json:"pokemon"`
+	} `json:"pokemon"`
+}
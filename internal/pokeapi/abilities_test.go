@@ -0,0 +1,31 @@
+package pokeapi
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetAbility(t *testing.T) {
+	client := newFakeClient(t)
+
+	ability, err := client.GetAbility(context.Background(), "lightning-rod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ability.Pokemon) == 0 || ability.Pokemon[0].Pokemon.Name != "pikachu" || !ability.Pokemon[0].IsHidden {
+		t.Errorf("expected pikachu to have lightning-rod hidden, got %v", ability.Pokemon)
+	}
+
+	static, err := client.GetAbility(context.Background(), "9")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, language := range []string{"en", "de"} {
+		if _, ok := LocalizedEffect(static.EffectEntries, language); !ok {
+			t.Errorf("expected %s effect for static", language)
+		}
+	}
+	if _, ok := LocalizedEffect(static.EffectEntries, "ja"); ok {
+		t.Errorf("expected no ja effect for static")
+	}
+}
@@ -0,0 +1,475 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokecache"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultUserAgent = "pokedexcli"
+const DefaultTimeout = 10 * time.Second
+const DefaultCacheInterval = 5 * time.Second
+const DefaultDecodedEntries = 256
+
+// Client fetches PokeAPI resources, caching the raw response bodies by URL
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	store      pokecache.Store
+	ownsStore  bool
+	userAgent  string
+	timeout    time.Duration
+	retry      RetryPolicy
+	debug      bool
+	offline    bool
+
+	rateLimit   float64
+	burst       int
+	maxInFlight int
+	limiter     *limiter
+
+	flights flightGroup
+
+	decodedEntries int
+	decoded        decodedCaches
+}
+
+// One TypedCache per resource type, so hits skip json.Unmarshal
+type decodedCaches struct {
+	locationAreas       *pokecache.TypedCache[*LocationAreas]
+	locationAreaDetails *pokecache.TypedCache[*LocationAreaDetail]
+	pokemonDetails      *pokecache.TypedCache[*PokemonDetail]
+	resourceLists       *pokecache.TypedCache[*NamedAPIResourceList]
+	generations         *pokecache.TypedCache[*Generation]
+	regions             *pokecache.TypedCache[*Region]
+	locations           *pokecache.TypedCache[*Location]
+	species             *pokecache.TypedCache[*PokemonSpecies]
+	evolutionChains     *pokecache.TypedCache[*EvolutionChain]
+	types               *pokecache.TypedCache[*Type]
+	moves               *pokecache.TypedCache[*Move]
+	abilities           *pokecache.TypedCache[*Ability]
+	versionGroups       *pokecache.TypedCache[*VersionGroup]
+}
+
+func newDecodedCaches(maxEntries int) decodedCaches {
+	return decodedCaches{
+		locationAreas:       pokecache.NewTypedCache[*LocationAreas](maxEntries),
+		locationAreaDetails: pokecache.NewTypedCache[*LocationAreaDetail](maxEntries),
+		pokemonDetails:      pokecache.NewTypedCache[*PokemonDetail](maxEntries),
+		resourceLists:       pokecache.NewTypedCache[*NamedAPIResourceList](maxEntries),
+		generations:         pokecache.NewTypedCache[*Generation](maxEntries),
+		regions:             pokecache.NewTypedCache[*Region](maxEntries),
+		locations:           pokecache.NewTypedCache[*Location](maxEntries),
+		species:             pokecache.NewTypedCache[*PokemonSpecies](maxEntries),
+		evolutionChains:     pokecache.NewTypedCache[*EvolutionChain](maxEntries),
+		types:               pokecache.NewTypedCache[*Type](maxEntries),
+		moves:               pokecache.NewTypedCache[*Move](maxEntries),
+		abilities:           pokecache.NewTypedCache[*Ability](maxEntries),
+		versionGroups:       pokecache.NewTypedCache[*VersionGroup](maxEntries),
+	}
+}
+
+type Option func(*Client)
+
+// Point the client at a different API root, e.g. an httptest.Server URL
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// Keep responses in store, e.g. a *pokecache.Cache, *pokecache.FileStore or
+// pokecache.NopStore
+func WithStore(store pokecache.Store) Option {
+	return func(c *Client) {
+		c.store = store
+	}
+}
+
+func WithCache(cache *pokecache.Cache) Option {
+	return WithStore(cache)
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// Overall time limit per request, applied on top of whichever http.Client is in use
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// How many decoded values to keep per resource type, zero means unbounded
+func WithDecodedEntries(maxEntries int) Option {
+	return func(c *Client) {
+		c.decodedEntries = maxEntries
+	}
+}
+
+// Serve exclusively from the store, never touching the network. Entries are
+// used however stale they are, and misses return an *OfflineError.
+func WithOffline(offline bool) Option {
+	return func(c *Client) {
+		c.offline = offline
+	}
+}
+
+// Print diagnostics such as retry attempts
+func WithDebug(debug bool) Option {
+	return func(c *Client) {
+		c.debug = debug
+	}
+}
+
+func NewClient(options ...Option) *Client {
+	client := Client{
+		baseURL:    BaseURL,
+		httpClient: http.DefaultClient,
+		userAgent:  DefaultUserAgent,
+		timeout:    DefaultTimeout,
+		retry:      DefaultRetryPolicy,
+
+		rateLimit:   DefaultRateLimit,
+		burst:       DefaultBurst,
+		maxInFlight: DefaultMaxInFlight,
+
+		decodedEntries: DefaultDecodedEntries,
+	}
+	for _, option := range options {
+		option(&client)
+	}
+
+	if client.store == nil {
+		client.store = pokecache.NewCache(DefaultCacheInterval)
+		client.ownsStore = true
+	}
+	if client.timeout > 0 {
+		// Copy so we don't modify a caller-supplied (or the default) http.Client
+		httpClient := *client.httpClient
+		httpClient.Timeout = client.timeout
+		client.httpClient = &httpClient
+	}
+	client.limiter = newLimiter(client.rateLimit, client.burst, client.maxInFlight)
+	client.decoded = newDecodedCaches(client.decodedEntries)
+
+	return &client
+}
+
+// Release the client's resources. A store passed in with WithStore belongs
+// to the caller, who is responsible for closing it.
+func (c *Client) Close() {
+	if closer, ok := c.store.(interface{ Close() }); ok && c.ownsStore {
+		closer.Close()
+	}
+}
+
+// The store backing this client, e.g. for reporting or clearing it
+func (c *Client) Store() pokecache.Store {
+	return c.store
+}
+
+// The URL of a single resource, escaping name so that user input can't
+// reach other endpoints, e.g. "../pokemon/pikachu"
+func (c *Client) resourceURL(kind string, name string) string {
+	escaped := neturl.PathEscape(name)
+	if name == "." || name == ".." {
+		// Not escaped by PathEscape, but would still climb out of kind
+		escaped = strings.ReplaceAll(name, ".", "%2E")
+	}
+	return c.baseURL + "/" + kind + "/" + escaped
+}
+
+// Get a page of location area list data - if url is nil or empty the first page will be fetched
+func (c *Client) GetLocationAreas(ctx context.Context, pageUrl *string) (*LocationAreas, error) {
+	var url string
+	if pageUrl == nil || *pageUrl == "" {
+		url = c.baseURL + "/location-area"
+	} else {
+		url = *pageUrl
+	}
+	return fetchJSON(ctx, c, url, c.decoded.locationAreas)
+}
+
+// PokeAPI accepts an ID anywhere it accepts a name, so areaName may also be
+// a numeric ID such as "1"
+func (c *Client) GetLocationAreaDetail(ctx context.Context, areaName string) (*LocationAreaDetail, error) {
+	return fetchJSON(ctx, c, c.resourceURL("location-area", areaName), c.decoded.locationAreaDetails)
+}
+
+func (c *Client) GetLocationAreaDetailByID(ctx context.Context, id int) (*LocationAreaDetail, error) {
+	return c.GetLocationAreaDetail(ctx, strconv.Itoa(id))
+}
+
+// pokemonName may also be a national Dex number such as "25"
+func (c *Client) GetPokemonDetail(ctx context.Context, pokemonName string) (*PokemonDetail, error) {
+	return fetchJSON(ctx, c, c.resourceURL("pokemon", pokemonName), c.decoded.pokemonDetails)
+}
+
+func (c *Client) GetPokemonDetailByID(ctx context.Context, id int) (*PokemonDetail, error) {
+	return c.GetPokemonDetail(ctx, strconv.Itoa(id))
+}
+
+// Get a page of the Pokémon list - if url is nil or empty the first page will be fetched
+func (c *Client) GetPokemonList(ctx context.Context, pageUrl *string) (*NamedAPIResourceList, error) {
+	url := c.baseURL + "/pokemon"
+	if pageUrl != nil && *pageUrl != "" {
+		url = *pageUrl
+	}
+	return fetchJSON(ctx, c, url, c.decoded.resourceLists)
+}
+
+// Generation by name (e.g. generation-i) or number
+func (c *Client) GetGeneration(ctx context.Context, generation string) (*Generation, error) {
+	return fetchJSON(ctx, c, c.resourceURL("generation", generation), c.decoded.generations)
+}
+
+func (c *Client) GetRegion(ctx context.Context, regionName string) (*Region, error) {
+	return fetchJSON(ctx, c, c.resourceURL("region", regionName), c.decoded.regions)
+}
+
+func (c *Client) GetLocation(ctx context.Context, locationName string) (*Location, error) {
+	return fetchJSON(ctx, c, c.resourceURL("location", locationName), c.decoded.locations)
+}
+
+// Species by name or national Dex number. Note that a species name may differ
+// from its Pokémon's names, e.g. the deoxys species has deoxys-normal.
+func (c *Client) GetPokemonSpecies(ctx context.Context, speciesName string) (*PokemonSpecies, error) {
+	return fetchJSON(ctx, c, c.resourceURL("pokemon-species", speciesName), c.decoded.species)
+}
+
+// Evolution chains only have IDs, use GetSpeciesEvolutionChain to find a
+// species' chain
+func (c *Client) GetEvolutionChain(ctx context.Context, id int) (*EvolutionChain, error) {
+	return fetchJSON(ctx, c, c.resourceURL("evolution-chain", strconv.Itoa(id)), c.decoded.evolutionChains)
+}
+
+// The evolution chain species belongs to
+func (c *Client) GetSpeciesEvolutionChain(ctx context.Context, species *PokemonSpecies) (*EvolutionChain, error) {
+	id, ok := IDFromURL(species.EvolutionChain.URL)
+	if !ok {
+		return nil, fmt.Errorf("%s has no evolution chain", species.Name)
+	}
+	return c.GetEvolutionChain(ctx, id)
+}
+
+func (c *Client) GetType(ctx context.Context, typeName string) (*Type, error) {
+	return fetchJSON(ctx, c, c.resourceURL("type", typeName), c.decoded.types)
+}
+
+// Get a page of the type list - if url is nil or empty the first page will be fetched
+func (c *Client) GetTypeList(ctx context.Context, pageUrl *string) (*NamedAPIResourceList, error) {
+	url := c.baseURL + "/type"
+	if pageUrl != nil && *pageUrl != "" {
+		url = *pageUrl
+	}
+	return fetchJSON(ctx, c, url, c.decoded.resourceLists)
+}
+
+// Fetch every type and build the chart of how they fare against each other
+func (c *Client) GetTypeChart(ctx context.Context) (*TypeChart, error) {
+	var types []*Type
+	var pageUrl *string
+	for {
+		page, err := c.GetTypeList(ctx, pageUrl)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range page.Results {
+			t, err := c.GetType(ctx, result.Name)
+			if err != nil {
+				return nil, err
+			}
+			types = append(types, t)
+		}
+		if page.Next == nil {
+			break
+		}
+		pageUrl = page.Next
+	}
+	return NewTypeChart(types), nil
+}
+
+func (c *Client) GetMove(ctx context.Context, moveName string) (*Move, error) {
+	return fetchJSON(ctx, c, c.resourceURL("move", moveName), c.decoded.moves)
+}
+
+// Get a page of the version group list - if url is nil or empty the first page will be fetched
+func (c *Client) GetVersionGroupList(ctx context.Context, pageUrl *string) (*NamedAPIResourceList, error) {
+	url := c.baseURL + "/version-group"
+	if pageUrl != nil && *pageUrl != "" {
+		url = *pageUrl
+	}
+	return fetchJSON(ctx, c, url, c.decoded.resourceLists)
+}
+
+func (c *Client) GetVersionGroup(ctx context.Context, groupName string) (*VersionGroup, error) {
+	return fetchJSON(ctx, c, c.resourceURL("version-group", groupName), c.decoded.versionGroups)
+}
+
+func (c *Client) GetAbility(ctx context.Context, abilityName string) (*Ability, error) {
+	return fetchJSON(ctx, c, c.resourceURL("ability", abilityName), c.decoded.abilities)
+}
+
+// Fetch the body at url, from the cache if it is still fresh (or at all, when
+// offline). Concurrent misses for the same url share a single fetch.
+func (c *Client) get(ctx context.Context, url string) (pokecache.Entry, error) {
+	entry, cached := c.store.GetEntry(url)
+	if cached && (c.offline || entry.Fresh(time.Now())) {
+		return entry, nil
+	}
+	if c.offline {
+		return pokecache.Entry{}, &OfflineError{url}
+	}
+
+	return c.flights.do(ctx, url, func() (pokecache.Entry, error) {
+		return c.getUncached(ctx, url)
+	})
+}
+
+// Fetch url over the network. Stale cache entries are revalidated with a
+// conditional request where the server gave us validators, and a 304
+// response refreshes the cached copy. Transient failures are retried
+// according to the client's RetryPolicy.
+func (c *Client) getUncached(ctx context.Context, url string) (pokecache.Entry, error) {
+	// Check again, as we may have queued behind a fetch that just finished.
+	// The lookup in get already counted towards the store's stats.
+	entry, cached := c.peek(url)
+	if cached && entry.Fresh(time.Now()) {
+		return entry, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return pokecache.Entry{}, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	var stale *pokecache.Entry
+	if cached {
+		setConditionalHeaders(req, entry)
+		stale = &entry
+	}
+
+	for attempt := 1; ; attempt++ {
+		fetched, err := c.fetch(req, url, stale)
+		if err == nil || attempt >= c.retry.MaxAttempts || !retryable(ctx, err) {
+			return fetched, err
+		}
+
+		delay, ok := c.retry.delay(attempt, err)
+		if !ok {
+			if c.debug {
+				fmt.Printf("DEBUG: attempt %d/%d failed: %v, not waiting that long to retry\n", attempt, c.retry.MaxAttempts, err)
+			}
+			return fetched, err
+		}
+		if c.debug {
+			fmt.Printf("DEBUG: attempt %d/%d failed: %v, retrying in %v\n", attempt, c.retry.MaxAttempts, err, delay)
+		}
+		err = sleep(ctx, delay)
+		if err != nil {
+			return pokecache.Entry{}, err
+		}
+	}
+}
+
+// Look url up without counting towards the store's stats, where it keeps any
+func (c *Client) peek(url string) (pokecache.Entry, bool) {
+	if peeker, ok := c.store.(interface{ Peek(string) (pokecache.Entry, bool) }); ok {
+		return peeker.Peek(url)
+	}
+	return c.store.GetEntry(url)
+}
+
+// A single attempt at req, caching the result under url. That must be the
+// URL req was built from rather than req.URL.String(), which may escape it
+// differently from the key callers look up. If stale is not nil req is a
+// conditional request for it.
+func (c *Client) fetch(req *http.Request, url string, stale *pokecache.Entry) (pokecache.Entry, error) {
+	release, err := c.limiter.acquire(req.Context())
+	if err != nil {
+		return pokecache.Entry{}, err
+	}
+	defer release()
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return pokecache.Entry{}, err
+	}
+	defer res.Body.Close()
+
+	now := time.Now()
+	meta, storable := cacheMetadata(res.Header, now)
+	if stale != nil && res.StatusCode == http.StatusNotModified {
+		refreshed := pokecache.Entry{Val: stale.Val, CreatedAt: now, Metadata: refreshedMetadata(stale.Metadata, meta)}
+		if storable {
+			c.store.AddEntry(url, refreshed)
+		}
+		return refreshed, nil
+	}
+
+	// Error bodies are never cached, so a later retry gets a clean slate
+	err = responseError(res, url, now)
+	if err != nil {
+		return pokecache.Entry{}, err
+	}
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return pokecache.Entry{}, err
+	}
+	fetched := pokecache.Entry{Val: bodyBytes, CreatedAt: now, Metadata: meta}
+	if storable {
+		c.store.AddEntry(url, fetched)
+	}
+
+	return fetched, nil
+}
+
+// Fetch and decode the resource at url. Decoded values are shared between
+// callers, so must not be modified.
+func fetchJSON[T any](ctx context.Context, c *Client, url string, decoded *pokecache.TypedCache[*T]) (*T, error) {
+	// Check the decoded copy against the stored entry's metadata first, so a
+	// hit needn't decompress the bytes only to throw them away
+	if meta, cached := c.store.Meta(url); cached && (c.offline || meta.Fresh(time.Now())) {
+		if data, ok := decoded.Get(url, meta.CreatedAt); ok {
+			// Still a use of the cached entry as far as its stats go
+			if recorder, ok := c.store.(interface{ RecordHit(string) }); ok {
+				recorder.RecordHit(url)
+			}
+			return data, nil
+		}
+	}
+
+	entry, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if data, ok := decoded.Get(url, entry.CreatedAt); ok {
+		return data, nil
+	}
+
+	var data T
+	err = json.Unmarshal(entry.Val, &data)
+	if err != nil {
+		return nil, err
+	}
+	decoded.Add(url, entry.CreatedAt, &data)
+	return &data, nil
+}
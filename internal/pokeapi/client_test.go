@@ -0,0 +1,224 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"github.com/venzy/pokedexcli/internal/pokeapi/fakeapi"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// A client for the fakeapi fixtures, closed along with its server when the
+// test ends. Options are applied after the base URL.
+func newFakeClient(t *testing.T, options ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(fakeapi.Default())
+	t.Cleanup(server.Close)
+	client := NewClient(append([]Option{WithBaseURL(server.URL + fakeapi.BasePath)}, options...)...)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestClientUsesBaseURLAndCache(t *testing.T) {
+	var requests atomic.Int32
+	var userAgent atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		userAgent.Store(r.Header.Get("User-Agent"))
+		if r.URL.Path != "/pokemon/pikachu" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"name": "pikachu", "base_experience": 112}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithUserAgent("pokedexcli-test"))
+	defer client.Close()
+
+	for i := 0; i < 2; i++ {
+		detail, err := client.GetPokemonDetail(context.Background(), "pikachu")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if detail.Name != "pikachu" || detail.BaseExperience != 112 {
+			t.Errorf("unexpected detail: %v %v", detail.Name, detail.BaseExperience)
+		}
+	}
+
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %v", requests.Load())
+	}
+	if userAgent.Load() != "pokedexcli-test" {
+		t.Errorf("expected custom user agent, got %v", userAgent.Load())
+	}
+}
+
+func TestClientLocationAreasFirstPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/location-area" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"count": 1, "next": null, "previous": null, "results": [{"name": "canalave-city-area", "url": ""}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+	empty := ""
+	for _, pageUrl := range []*string{nil, &empty} {
+		data, err := client.GetLocationAreas(context.Background(), pageUrl)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(data.Results) != 1 || data.Results[0].Name != "canalave-city-area" {
+			t.Errorf("unexpected results: %v", data.Results)
+		}
+	}
+}
+
+func TestClientDetailByID(t *testing.T) {
+	client := newFakeClient(t)
+	pokemon, err := client.GetPokemonDetailByID(context.Background(), 25)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pokemon.Name != "pikachu" {
+		t.Errorf("expected pikachu, got %s", pokemon.Name)
+	}
+	area, err := client.GetLocationAreaDetailByID(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if area.Name != "canalave-city-area" {
+		t.Errorf("expected canalave-city-area, got %s", area.Name)
+	}
+}
+
+func TestClientEscapesNames(t *testing.T) {
+	var requests atomic.Int32
+	var paths sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		paths.Store(r.URL.EscapedPath(), true)
+		if r.URL.Path != "/pokemon/flabébé" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name": "flabébé"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+	for i := 0; i < 2; i++ {
+		detail, err := client.GetPokemonDetail(context.Background(), "flabébé")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if detail.Name != "flabébé" {
+			t.Errorf("unexpected name %s", detail.Name)
+		}
+	}
+	// The response must be cached under the key it is looked up by
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %v", requests.Load())
+	}
+
+	// Names stay within their own endpoint
+	for _, name := range []string{"../pokemon/flabébé", ".."} {
+		_, err := client.GetLocationAreaDetail(context.Background(), name)
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) {
+			t.Errorf("%s: expected NotFoundError, got %v", name, err)
+		}
+	}
+	for _, path := range []string{"/location-area/..%2Fpokemon%2Fflab%C3%A9b%C3%A9", "/location-area/%2E%2E"} {
+		if _, ok := paths.Load(path); !ok {
+			t.Errorf("expected a request for %s", path)
+		}
+	}
+}
+
+func TestClientErrorResponses(t *testing.T) {
+	cases := []struct {
+		status int
+		check  func(err error) bool
+	}{
+		{
+			status: http.StatusNotFound,
+			check: func(err error) bool {
+				var target *NotFoundError
+				return errors.As(err, &target)
+			},
+		},
+		{
+			status: http.StatusTooManyRequests,
+			check: func(err error) bool {
+				var target *RateLimitedError
+				return errors.As(err, &target) && target.RetryAfter == 2*time.Second
+			},
+		},
+		{
+			status: http.StatusBadGateway,
+			check: func(err error) bool {
+				var target *ServerError
+				return errors.As(err, &target) && target.StatusCode == http.StatusBadGateway
+			},
+		},
+		{
+			status: http.StatusForbidden,
+			check: func(err error) bool {
+				var target *StatusError
+				return errors.As(err, &target) && target.StatusCode == http.StatusForbidden
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(http.StatusText(c.status), func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Retry-After", "2")
+				w.WriteHeader(c.status)
+				w.Write([]byte("Not Found"))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+			defer client.Close()
+			for i := 0; i < 2; i++ {
+				_, err := client.GetPokemonDetail(context.Background(), "notapokemon")
+				if !c.check(err) {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+			// Error responses must not be cached
+			if requests.Load() != 2 {
+				t.Errorf("expected 2 requests, got %v", requests.Load())
+			}
+		})
+	}
+}
+
+func TestClientCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hang until the client gives up
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := client.GetPokemonDetail(ctx, "pikachu")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
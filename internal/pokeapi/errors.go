@@ -0,0 +1,95 @@
+package pokeapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NotFoundError means PokeAPI has no resource at URL, typically a misspelt name
+type NotFoundError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("not found (%d): %s", e.StatusCode, e.URL)
+}
+
+// RateLimitedError means PokeAPI asked us to slow down. RetryAfter is zero
+// if the server didn't say how long to wait.
+type RateLimitedError struct {
+	StatusCode int
+	URL        string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited (%d), retry after %v: %s", e.StatusCode, e.RetryAfter, e.URL)
+	}
+	return fmt.Sprintf("rate limited (%d): %s", e.StatusCode, e.URL)
+}
+
+// ServerError is a 5xx response, which may succeed if tried again later
+type ServerError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("server error (%d): %s", e.StatusCode, e.URL)
+}
+
+// StatusError is any other non-2xx response
+type StatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status (%d): %s", e.StatusCode, e.URL)
+}
+
+// Turn a non-2xx response into one of the typed errors above, nil otherwise
+func responseError(res *http.Response, url string, now time.Time) error {
+	code := res.StatusCode
+	switch {
+	case code >= 200 && code < 300:
+		return nil
+	case code == http.StatusNotFound:
+		return &NotFoundError{code, url}
+	case code == http.StatusTooManyRequests:
+		return &RateLimitedError{code, url, parseRetryAfter(res.Header.Get("Retry-After"), now)}
+	case code >= 500:
+		return &ServerError{code, url}
+	default:
+		return &StatusError{code, url}
+	}
+}
+
+// Retry-After is either a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	when, err := http.ParseTime(value)
+	if err == nil && when.After(now) {
+		return when.Sub(now)
+	}
+	return 0
+}
+
+// OfflineError means the client is offline and has no local copy of URL
+type OfflineError struct {
+	URL string
+}
+
+func (e *OfflineError) Error() string {
+	return fmt.Sprintf("not available offline: %s", e.URL)
+}
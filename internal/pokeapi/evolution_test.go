@@ -0,0 +1,55 @@
+package pokeapi
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEvolutionDetailString(t *testing.T) {
+	level := 36
+	happiness := 220
+	female := 1
+	higherAttack := 1
+	cases := []struct {
+		detail   EvolutionDetail
+		expected string
+	}{
+		{EvolutionDetail{Trigger: NamedAPIResource{Name: "level-up"}, MinLevel: &level}, "level 36"},
+		{EvolutionDetail{Trigger: NamedAPIResource{Name: "level-up"}, MinHappiness: &happiness, TimeOfDay: "day"}, "level up, friendship 220+, during the day"},
+		{EvolutionDetail{Trigger: NamedAPIResource{Name: "use-item"}, Item: &NamedAPIResource{Name: "dawn-stone"}, Gender: &female}, "use dawn-stone, female"},
+		{EvolutionDetail{Trigger: NamedAPIResource{Name: "trade"}, HeldItem: &NamedAPIResource{Name: "metal-coat"}}, "trade, holding metal-coat"},
+		{EvolutionDetail{Trigger: NamedAPIResource{Name: "trade"}, TradeSpecies: &NamedAPIResource{Name: "shelmet"}}, "trade, for shelmet"},
+		{EvolutionDetail{Trigger: NamedAPIResource{Name: "level-up"}, MinLevel: &level, RelativePhysicalStats: &higherAttack}, "level 36, attack > defense"},
+		{EvolutionDetail{Trigger: NamedAPIResource{Name: "level-up"}, MinLevel: &level, NeedsOverworldRain: true}, "level 36, while raining"},
+		{EvolutionDetail{Trigger: NamedAPIResource{Name: "tower-of-darkness"}}, "tower of darkness"},
+	}
+	for _, c := range cases {
+		if described := c.detail.String(); described != c.expected {
+			t.Errorf("expected %q, got %q", c.expected, described)
+		}
+	}
+}
+
+func TestGetSpeciesEvolutionChain(t *testing.T) {
+	client := newFakeClient(t)
+	ctx := context.Background()
+
+	species, err := client.GetPokemonSpecies(ctx, "eevee")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chain, err := client.GetSpeciesEvolutionChain(ctx, species)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chain.Chain.Species.Name != "eevee" || len(chain.Chain.EvolvesTo) != 8 {
+		t.Fatalf("unexpected chain rooted at %s with %d branches", chain.Chain.Species.Name, len(chain.Chain.EvolvesTo))
+	}
+	leafeon, ok := chain.Chain.Find("leafeon")
+	if !ok || len(leafeon.EvolutionDetails) != 2 {
+		t.Errorf("expected leafeon with 2 ways to evolve, got %v", leafeon)
+	}
+	if _, ok := chain.Chain.Find("pikachu"); ok {
+		t.Errorf("didn't expect pikachu in eevee's chain")
+	}
+}
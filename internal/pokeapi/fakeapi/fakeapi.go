@@ -0,0 +1,227 @@
+// Package fakeapi is a stand-in for PokeAPI serving recorded JSON fixtures,
+// for tests and offline development. Point a client at it with
+//
+//	server := httptest.NewServer(fakeapi.Default())
+//	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL + fakeapi.BasePath))
+package fakeapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fixtures are laid out as <resource>/<name>.json, e.g. pokemon/pikachu.json
+//
+//go:embed fixtures
+var fixtures embed.FS
+
+// Resources are served under this path, as on the real PokeAPI
+const BasePath = "/api/v2"
+
+// Page size when a list request has no limit, as on the real PokeAPI
+const DefaultLimit = 20
+
+// URLs inside fixtures point at the real PokeAPI, and are rewritten to point
+// at whichever host the request came in on
+const recordedBaseURL = "https://pokeapi.co" + BasePath
+
+// Server serves a fixed set of resources loaded from fixtures
+type Server struct {
+	// Resources keyed on type, each sorted by ID
+	resources map[string][]resource
+}
+
+type resource struct {
+	id   int
+	name string
+	body []byte
+}
+
+// Default serves the fixtures embedded in this package
+func Default() *Server {
+	fixtureFS, err := fs.Sub(fixtures, "fixtures")
+	if err != nil {
+		panic(err)
+	}
+	server, err := New(fixtureFS)
+	if err != nil {
+		// Embedded fixtures are checked by the tests, so this is a bug
+		panic(err)
+	}
+	return server
+}
+
+// New serves fixtures from fixtureFS, which must be laid out as
+// <resource>/<name>.json with each file holding an object with an id and
+// usually a name. Unnamed resources such as evolution chains are only
+// found by id.
+func New(fixtureFS fs.FS) (*Server, error) {
+	server := &Server{resources: make(map[string][]resource)}
+	files, err := fs.Glob(fixtureFS, "*/*.json")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		body, err := fs.ReadFile(fixtureFS, file)
+		if err != nil {
+			return nil, err
+		}
+		var ident struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		err = json.Unmarshal(body, &ident)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", file, err)
+		}
+		if ident.ID <= 0 {
+			return nil, fmt.Errorf("fixture %s: missing id", file)
+		}
+		kind := path.Dir(file)
+		server.resources[kind] = append(server.resources[kind], resource{ident.ID, ident.Name, body})
+	}
+	for _, list := range server.resources {
+		sort.Slice(list, func(i, j int) bool { return list[i].id < list[j].id })
+	}
+	return server, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, BasePath+"/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	kind, key, _ := strings.Cut(strings.TrimSuffix(rest, "/"), "/")
+	list, ok := s.resources[kind]
+	if !ok || strings.Contains(key, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	if key == "" {
+		s.serveList(w, r, kind, list)
+		return
+	}
+	for _, res := range list {
+		if (res.name != "" && res.name == key) || strconv.Itoa(res.id) == key {
+			serveJSON(w, r, rewriteURLs(res.body, baseURL(r)))
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// Serve a page of a resource list, honouring offset and limit
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request, kind string, list []resource) {
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(r, "limit", DefaultLimit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if limit == 0 {
+		// Otherwise next would point back at this same empty page forever
+		limit = DefaultLimit
+	}
+
+	base := baseURL(r)
+	pageURL := func(offset int) *string {
+		url := fmt.Sprintf("%s/%s/?offset=%d&limit=%d", base, kind, offset, limit)
+		return &url
+	}
+
+	type namedResource struct {
+		Name string `json:"name,omitempty"`
+		URL  string `json:"url"`
+	}
+	page := struct {
+		Count    int             `json:"count"`
+		Next     *string         `json:"next"`
+		Previous *string         `json:"previous"`
+		Results  []namedResource `json:"results"`
+	}{
+		Count:   len(list),
+		Results: []namedResource{},
+	}
+	end := min(offset+limit, len(list))
+	for _, res := range list[min(offset, len(list)):end] {
+		page.Results = append(page.Results, namedResource{res.name, fmt.Sprintf("%s/%s/%d/", base, kind, res.id)})
+	}
+	if end < len(list) {
+		page.Next = pageURL(end)
+	}
+	if offset > 0 {
+		page.Previous = pageURL(max(offset-limit, 0))
+	}
+
+	body, err := json.Marshal(page)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	serveJSON(w, r, body)
+}
+
+// Serve body with the caching headers PokeAPI sends, answering conditional
+// requests with 304 Not Modified
+func serveJSON(w http.ResponseWriter, r *http.Request, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("Cache-Control", "public, max-age=86400, s-maxage=86400")
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// The base URL clients reached us on, so links in responses lead back here
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + BasePath
+}
+
+func rewriteURLs(body []byte, base string) []byte {
+	return bytes.ReplaceAll(body, []byte(recordedBaseURL), []byte(base))
+}
+
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", name, value)
+	}
+	return n, nil
+}
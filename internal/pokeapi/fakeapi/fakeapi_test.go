@@ -0,0 +1,146 @@
+package fakeapi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"github.com/venzy/pokedexcli/internal/pokeapi/fakeapi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newClient(t *testing.T) (*pokeapi.Client, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(fakeapi.Default())
+	t.Cleanup(server.Close)
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL + fakeapi.BasePath))
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+func TestPagination(t *testing.T) {
+	client, server := newClient(t)
+	ctx := context.Background()
+
+	first := server.URL + fakeapi.BasePath + "/location-area?limit=2"
+	page, err := client.GetLocationAreas(ctx, &first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Count != 23 || len(page.Results) != 2 {
+		t.Fatalf("expected 2 of 23 areas, got %d of %d", len(page.Results), page.Count)
+	}
+	if page.Results[0].Name != "canalave-city-area" || page.Results[1].Name != "eterna-city-area" {
+		t.Errorf("unexpected first page: %v", page.Results)
+	}
+	if page.Previous != nil {
+		t.Errorf("expected no previous page, got %v", *page.Previous)
+	}
+	if page.Next == nil || !strings.HasPrefix(*page.Next, server.URL) {
+		t.Fatalf("expected next page on the fake server, got %v", page.Next)
+	}
+
+	if !strings.Contains(*page.Next, "offset=2&limit=2") {
+		t.Errorf("unexpected next page: %s", *page.Next)
+	}
+
+	tail := server.URL + fakeapi.BasePath + "/location-area?offset=22&limit=2"
+	last, err := client.GetLocationAreas(ctx, &tail)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(last.Results) != 1 || last.Results[0].Name != "mt-coronet-b1f" {
+		t.Errorf("unexpected last page: %v", last.Results)
+	}
+	if last.Next != nil {
+		t.Errorf("expected no next page, got %v", *last.Next)
+	}
+	if last.Previous == nil || !strings.Contains(*last.Previous, "offset=20&limit=2") {
+		t.Errorf("expected previous page at offset 20, got %v", last.Previous)
+	}
+}
+
+func TestDefaultPageSize(t *testing.T) {
+	client, _ := newClient(t)
+
+	page, err := client.GetLocationAreas(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Results) != fakeapi.DefaultLimit || page.Next == nil {
+		t.Errorf("expected a full page with more to come, got %d of %d, next %v", len(page.Results), page.Count, page.Next)
+	}
+}
+
+func TestDetailByNameAndID(t *testing.T) {
+	client, server := newClient(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"pikachu", "25"} {
+		detail, err := client.GetPokemonDetail(ctx, key)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", key, err)
+		}
+		if detail.Name != "pikachu" || detail.BaseExperience != 112 {
+			t.Errorf("%s: unexpected detail: %v %v", key, detail.Name, detail.BaseExperience)
+		}
+	}
+
+	area, err := client.GetLocationAreaDetail(ctx, "canalave-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(area.PokemonEncounters) != 2 {
+		t.Errorf("expected 2 encounters, got %d", len(area.PokemonEncounters))
+	}
+
+	// Links inside fixtures lead back to the fake server
+	region, err := client.GetRegion(ctx, "sinnoh")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, location := range region.Locations {
+		if !strings.HasPrefix(location.URL, server.URL+fakeapi.BasePath) {
+			t.Errorf("expected location URL on the fake server, got %s", location.URL)
+		}
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	client, _ := newClient(t)
+
+	_, err := client.GetPokemonDetail(context.Background(), "missingno")
+	var notFound *pokeapi.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("expected NotFoundError, got %v", err)
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	server := httptest.NewServer(fakeapi.Default())
+	defer server.Close()
+	url := server.URL + fakeapi.BasePath + "/pokemon/magikarp/"
+
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+	etag := res.Header.Get("ETag")
+	if res.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d %q", res.StatusCode, etag)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("If-None-Match", etag)
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotModified {
+		t.Errorf("expected 304, got %d", res.StatusCode)
+	}
+}
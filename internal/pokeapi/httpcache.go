@@ -0,0 +1,74 @@
+package pokeapi
+
+import (
+	"github.com/venzy/pokedexcli/internal/pokecache"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Work out how long a response may be cached for, and how to revalidate it.
+// Returns false if the server asked us not to store the response at all.
+func cacheMetadata(header http.Header, now time.Time) (pokecache.Metadata, bool) {
+	meta := pokecache.Metadata{
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+
+	maxAge, hasMaxAge := -1, false
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store":
+			return meta, false
+		case "no-cache":
+			// Storable, but must be revalidated before every use
+			maxAge, hasMaxAge = 0, true
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err == nil && !hasMaxAge {
+				maxAge, hasMaxAge = seconds, true
+			}
+		}
+	}
+
+	if hasMaxAge {
+		// Discount time the response already spent in shared caches on the way
+		age, err := strconv.Atoi(header.Get("Age"))
+		if err == nil && age > 0 {
+			maxAge -= age
+		}
+		meta.ExpiresAt = now.Add(time.Duration(max(maxAge, 0)) * time.Second)
+	} else if expires := header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			// An invalid Expires means already expired
+			expiresAt = now
+		}
+		meta.ExpiresAt = expiresAt
+	}
+
+	return meta, true
+}
+
+// Attach validators from a stale cached entry so the server can answer 304
+func setConditionalHeaders(req *http.Request, entry pokecache.Entry) {
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// A 304 may omit validators, in which case the ones we already had still apply
+func refreshedMetadata(stale pokecache.Metadata, fresh pokecache.Metadata) pokecache.Metadata {
+	if fresh.ETag == "" {
+		fresh.ETag = stale.ETag
+	}
+	if fresh.LastModified == "" {
+		fresh.LastModified = stale.LastModified
+	}
+	return fresh
+}
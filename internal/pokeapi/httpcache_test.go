@@ -0,0 +1,247 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokecache"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheMetadata(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		header    http.Header
+		storable  bool
+		expiresAt time.Time
+	}{
+		{
+			header:    http.Header{},
+			storable:  true,
+			expiresAt: time.Time{},
+		},
+		{
+			header:    http.Header{"Cache-Control": {"public, max-age=86400, s-maxage=86400"}},
+			storable:  true,
+			expiresAt: now.Add(24 * time.Hour),
+		},
+		{
+			header:    http.Header{"Cache-Control": {"max-age=60"}, "Age": {"20"}},
+			storable:  true,
+			expiresAt: now.Add(40 * time.Second),
+		},
+		{
+			header:    http.Header{"Cache-Control": {"no-cache"}},
+			storable:  true,
+			expiresAt: now,
+		},
+		{
+			header:   http.Header{"Cache-Control": {"no-store"}},
+			storable: false,
+		},
+		{
+			header:    http.Header{"Expires": {"Wed, 01 Jan 2025 01:00:00 GMT"}},
+			storable:  true,
+			expiresAt: now.Add(time.Hour),
+		},
+	}
+
+	for _, c := range cases {
+		meta, storable := cacheMetadata(c.header, now)
+		if storable != c.storable {
+			t.Errorf("%v: expected storable %v, got %v", c.header, c.storable, storable)
+			continue
+		}
+		if storable && !meta.ExpiresAt.Equal(c.expiresAt) {
+			t.Errorf("%v: expected expiry %v, got %v", c.header, c.expiresAt, meta.ExpiresAt)
+		}
+	}
+}
+
+func TestClientRevalidatesStaleEntries(t *testing.T) {
+	var fullResponses, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=0")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+	for i := 0; i < 3; i++ {
+		detail, err := client.GetPokemonDetail(context.Background(), "pikachu")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if detail.Name != "pikachu" {
+			t.Errorf("expected pikachu, got %v", detail.Name)
+		}
+	}
+
+	if fullResponses != 1 || notModified != 2 {
+		t.Errorf("expected 1 full response and 2 revalidations, got %v and %v", fullResponses, notModified)
+	}
+}
+
+func TestClientRevalidatesAfterReap(t *testing.T) {
+	var fullResponses, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=0")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	// Memory only, reaping far more often than the client fetches
+	client := NewClient(WithBaseURL(server.URL), WithCache(pokecache.NewCache(time.Millisecond)))
+	defer client.Close()
+	for i := 0; i < 2; i++ {
+		_, err := client.GetPokemonDetail(context.Background(), "pikachu")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if fullResponses.Load() != 1 || notModified.Load() != 1 {
+		t.Errorf("expected 1 full response and 1 revalidation, got %v and %v", fullResponses.Load(), notModified.Load())
+	}
+}
+
+func TestClientCountsDecodedHits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Hour)
+	defer cache.Close()
+	client := NewClient(WithBaseURL(server.URL), WithCache(cache))
+	defer client.Close()
+	for i := 0; i < 3; i++ {
+		_, err := client.GetPokemonDetail(context.Background(), "pikachu")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if stats := cache.Stats(); stats.Hits != 2 {
+		t.Errorf("expected repeat lookups served decoded to count as 2 hits, got %+v", stats)
+	}
+}
+
+func TestClientCountsEachLookupOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Hour)
+	defer cache.Close()
+	client := NewClient(WithBaseURL(server.URL), WithCache(cache))
+	defer client.Close()
+
+	_, err := client.GetPokemonDetail(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats := cache.Stats(); stats.Hits != 0 || stats.Misses != 1 {
+		t.Errorf("expected 1 miss after the first fetch, got %+v", stats)
+	}
+	_, err = client.GetPokemonDetail(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss after a repeat fetch, got %+v", stats)
+	}
+}
+
+func TestClientReusesDecodedValuesUntilRefreshed(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Cache-Control", "max-age=3600")
+			w.Write([]byte(`{"name": "pikachu", "base_experience": 112}`))
+			return
+		}
+		w.Write([]byte(`{"name": "pikachu", "base_experience": 113}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+
+	first, err := client.GetPokemonDetail(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := client.GetPokemonDetail(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second {
+		t.Errorf("expected a cache hit to reuse the decoded value")
+	}
+
+	// Replace the underlying bytes, the decoded value must follow
+	pokecache.PurgeStore(client.Store(), server.URL)
+	third, err := client.GetPokemonDetail(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if third == first || third.BaseExperience != 113 {
+		t.Errorf("expected a refetch to be decoded afresh, got %v", third.BaseExperience)
+	}
+}
+
+// Decoded cache hits on a compressed store shouldn't pay for decompressing
+// the entry, so this should be close to the uncompressed case
+func BenchmarkFetchJSONHit(b *testing.B) {
+	body := []byte(`{"name": "pikachu", "base_experience": 112, "moves": [` + strings.Repeat(`{"move": {"name": "thunder-shock", "url": ""}},`, 200) + `{}]}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	for _, compress := range []bool{false, true} {
+		b.Run(fmt.Sprintf("compress=%v", compress), func(b *testing.B) {
+			cache := pokecache.NewCache(time.Hour, pokecache.WithCompression(compress))
+			client := NewClient(WithBaseURL(server.URL), WithCache(cache))
+			defer client.Close()
+			defer cache.Close()
+			_, err := client.GetPokemonDetail(context.Background(), "pikachu")
+			if err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := client.GetPokemonDetail(context.Background(), "pikachu")
+				if err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
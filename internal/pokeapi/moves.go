@@ -0,0 +1,54 @@
+package pokeapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Move is an attack or other action a Pokémon can take in battle
+type Move struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Names []Name `json:"names"`
+	// Power and Accuracy are nil for moves that don't use them, e.g. status
+	// moves have no power and some moves never miss
+	Power        *int             `json:"power"`
+	Accuracy     *int             `json:"accuracy"`
+	PP           *int             `json:"pp"`
+	Priority     int              `json:"priority"`
+	Type         NamedAPIResource `json:"type"`
+	DamageClass  NamedAPIResource `json:"damage_class"`
+	EffectChance *int             `json:"effect_chance"`
+	// Effect text may refer to EffectChance as $effect_chance
+	EffectEntries     []VerboseEffect `json:"effect_entries"`
+	FlavorTextEntries []struct {
+		FlavorText   string           `json:"flavor_text"`
+		Language     NamedAPIResource `json:"language"`
+		VersionGroup NamedAPIResource `json:"version_group"`
+	} `json:"flavor_text_entries"`
+}
+
+// VersionGroup is a set of games released together, e.g. red-blue, which
+// share move learnsets
+type VersionGroup struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Release order. IDs don't follow it, as some older games were added
+	// to PokeAPI late.
+	Order      int                `json:"order"`
+	Generation NamedAPIResource   `json:"generation"`
+	Versions   []NamedAPIResource `json:"versions"`
+}
+
+// The short effect in the given language, with the effect chance filled in
+func (m *Move) ShortEffect(language string) (string, bool) {
+	entry, ok := LocalizedEffect(m.EffectEntries, language)
+	if !ok {
+		return "", false
+	}
+	effect := entry.ShortEffect
+	if m.EffectChance != nil {
+		effect = strings.ReplaceAll(effect, "$effect_chance", strconv.Itoa(*m.EffectChance))
+	}
+	return effect, true
+}
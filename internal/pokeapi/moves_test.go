@@ -0,0 +1,53 @@
+package pokeapi
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetMove(t *testing.T) {
+	client := newFakeClient(t)
+
+	move, err := client.GetMove(context.Background(), "thunderbolt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if move.Type.Name != "electric" || move.Power == nil || *move.Power != 90 {
+		t.Errorf("unexpected move: %v %v", move.Type.Name, move.Power)
+	}
+	effect, ok := move.ShortEffect("en")
+	if !ok || effect != "Has a 10% chance to paralyze the target." {
+		t.Errorf("unexpected effect: %q %v", effect, ok)
+	}
+	if _, ok := move.ShortEffect("ja"); ok {
+		t.Errorf("expected no ja effect")
+	}
+
+	growl, err := client.GetMove(context.Background(), "growl")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if growl.Power != nil {
+		t.Errorf("expected status move to have no power, got %v", *growl.Power)
+	}
+}
+
+func TestGetVersionGroup(t *testing.T) {
+	client := newFakeClient(t)
+
+	yellow, err := client.GetVersionGroup(context.Background(), "yellow")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	japan, err := client.GetVersionGroup(context.Background(), "red-green-japan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Added to PokeAPI later, but released earlier
+	if japan.ID < yellow.ID || japan.Order > yellow.Order {
+		t.Errorf("expected red-green-japan to have a higher ID but lower order, got %+v and %+v", japan, yellow)
+	}
+	if len(yellow.Versions) != 1 || yellow.Versions[0].Name != "yellow" {
+		t.Errorf("unexpected versions: %v", yellow.Versions)
+	}
+}
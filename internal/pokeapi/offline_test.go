@@ -0,0 +1,49 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"github.com/venzy/pokedexcli/internal/pokecache"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOfflineServesOnlyFromStore(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"name": "network"}`))
+	}))
+	defer server.Close()
+
+	store := pokecache.NewFileStore(t.TempDir())
+	// Long stale, but offline that doesn't matter
+	store.AddEntry(server.URL+"/pokemon/pikachu", pokecache.Entry{
+		Val:      []byte(`{"name": "pikachu"}`),
+		Metadata: pokecache.Metadata{ExpiresAt: time.Now().Add(-time.Hour)},
+	})
+
+	client := NewClient(WithBaseURL(server.URL), WithStore(store), WithOffline(true))
+	defer client.Close()
+
+	detail, err := client.GetPokemonDetail(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if detail.Name != "pikachu" {
+		t.Errorf("expected the stored pikachu, got %v", detail.Name)
+	}
+
+	_, err = client.GetPokemonDetail(context.Background(), "raichu")
+	var offline *OfflineError
+	if !errors.As(err, &offline) || offline.URL != server.URL+"/pokemon/raichu" {
+		t.Errorf("expected OfflineError, got %v", err)
+	}
+
+	if requests.Load() != 0 {
+		t.Errorf("expected no network requests, got %v", requests.Load())
+	}
+}
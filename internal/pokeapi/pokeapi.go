@@ -1,16 +1,19 @@
 package pokeapi
 
 import (
-	"encoding/json"
-	"github.com/venzy/pokedexcli/internal/pokecache"
-	"io"
-	"net/http"
-	"time"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 const BaseURL = "https://pokeapi.co/api/v2"
 
-var cache *pokecache.Cache = pokecache.NewCache(5 * time.Second)
+// Shared client behind the package-level Get functions, only built on first
+// use so merely importing the package doesn't start its cache's reaper
+var defaultClient = sync.OnceValue(func() *Client {
+	return NewClient()
+})
 
 type LocationAreas struct {
 	Count    int    `json:"count"`
@@ -22,37 +25,6 @@ type LocationAreas struct {
 	} `json:"results"`
 }
 
-// Get a page of location area list data - if url is nil or empty the first page will be fetched
-func GetLocationAreas(pageUrl *string) (*LocationAreas, error) {
-	var url string
-	if pageUrl == nil || *pageUrl == "" {
-		url = BaseURL + "/location-area"
-	} else {
-		url = *pageUrl
-	}
-
-	bodyBytes, ok := cache.Get(url)
-	if !ok {
-		res, err := http.Get(url)
-		if err != nil {
-			return nil, err
-		}
-		defer res.Body.Close()
-		bodyBytes, err = io.ReadAll(res.Body)
-		if err != nil {
-			return nil, err
-		}
-		cache.Add(url, bodyBytes)
-	}
-
-	var data LocationAreas
-	err := json.Unmarshal(bodyBytes, &data)
-	if err != nil {
-		return nil, err
-	}
-	return &data, nil
-}
-
 type LocationAreaDetail struct {
 	EncounterMethodRates []struct {
 		EncounterMethod struct {
@@ -106,31 +78,6 @@ type LocationAreaDetail struct {
 	} `json:"pokemon_encounters"`
 }
 
-func GetLocationAreaDetail(areaName string) (*LocationAreaDetail, error) {
-	var url string = BaseURL + "/location-area/" + areaName
-	bodyBytes, ok := cache.Get(url)
-	if !ok {
-		res, err := http.Get(url)
-		if err != nil {
-			return nil, err
-		}
-		defer res.Body.Close()
-		bodyBytes, err = io.ReadAll(res.Body)
-		if err != nil {
-			return nil, err
-		}
-		cache.Add(url, bodyBytes)
-	}
-
-	var data LocationAreaDetail
-	err := json.Unmarshal(bodyBytes, &data)
-	if err != nil {
-		return nil, err
-	}
-
-	return &data, nil
-}
-
 type PokemonDetail struct {
 	Abilities []struct {
 		Ability struct {
@@ -402,27 +349,134 @@ type PokemonDetail struct {
 	Weight int `json:"weight"`
 }
 
-func GetPokemonDetail(pokemonName string) (*PokemonDetail, error) {
-	var url string = BaseURL + "/pokemon/" + pokemonName
-	bodyBytes, ok := cache.Get(url)
-	if !ok {
-		res, err := http.Get(url)
-		if err != nil {
-			return nil, err
+// Get a page of location area list data - if url is nil or empty the first page will be fetched
+func GetLocationAreas(ctx context.Context, pageUrl *string) (*LocationAreas, error) {
+	return defaultClient().GetLocationAreas(ctx, pageUrl)
+}
+
+func GetLocationAreaDetail(ctx context.Context, areaName string) (*LocationAreaDetail, error) {
+	return defaultClient().GetLocationAreaDetail(ctx, areaName)
+}
+
+func GetPokemonDetail(ctx context.Context, pokemonName string) (*PokemonDetail, error) {
+	return defaultClient().GetPokemonDetail(ctx, pokemonName)
+}
+
+// NamedAPIResource is how PokeAPI refers from one resource to another
+type NamedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// NamedAPIResourceList is one page of a resource listing, e.g. /pokemon
+type NamedAPIResourceList struct {
+	Count    int                `json:"count"`
+	Next     *string            `json:"next"`
+	Previous *string            `json:"previous"`
+	Results  []NamedAPIResource `json:"results"`
+}
+
+// Name is a resource's name in one language
+type Name struct {
+	Name     string           `json:"name"`
+	Language NamedAPIResource `json:"language"`
+}
+
+// FlavorText is in-game descriptive text, in one language as it appeared in
+// one game version
+type FlavorText struct {
+	FlavorText string           `json:"flavor_text"`
+	Language   NamedAPIResource `json:"language"`
+	Version    NamedAPIResource `json:"version"`
+}
+
+// VerboseEffect describes what a move or ability does, in one language
+type VerboseEffect struct {
+	Effect      string           `json:"effect"`
+	ShortEffect string           `json:"short_effect"`
+	Language    NamedAPIResource `json:"language"`
+}
+
+// The effect in the given language, e.g. "en". Few effects are translated,
+// so callers will usually want to fall back to DefaultLanguage.
+func LocalizedEffect(entries []VerboseEffect, language string) (VerboseEffect, bool) {
+	for _, entry := range entries {
+		if entry.Language.Name == language {
+			return entry, true
 		}
-		defer res.Body.Close()
-		bodyBytes, err = io.ReadAll(res.Body)
-		if err != nil {
-			return nil, err
+	}
+	return VerboseEffect{}, false
+}
+
+// Language used when the user hasn't chosen one, and as a fallback for
+// text that hasn't been translated
+const DefaultLanguage = "en"
+
+// The name in the given language, e.g. "en" or "ja"
+func LocalizedName(names []Name, language string) (string, bool) {
+	for _, name := range names {
+		if name.Language.Name == language {
+			return name.Name, true
 		}
-		cache.Add(url, bodyBytes)
 	}
+	return "", false
+}
 
-	var data PokemonDetail
-	err := json.Unmarshal(bodyBytes, &data)
-	if err != nil {
-		return nil, err
+// The flavor text in the given language from the given game version, or
+// from the latest version with any if version is empty
+func LocalizedFlavorText(entries []FlavorText, language string, version string) (FlavorText, bool) {
+	var found FlavorText
+	ok := false
+	for _, entry := range entries {
+		if entry.Language.Name != language {
+			continue
+		}
+		if entry.Version.Name == version {
+			return entry, true
+		}
+		if version == "" {
+			// Entries are listed oldest version first
+			found, ok = entry, true
+		}
 	}
+	return found, ok
+}
+
+// Flavor text is laid out for small in-game text boxes, with hard line and
+// page breaks and soft hyphens that read badly anywhere else
+func CleanFlavorText(text string) string {
+	text = strings.ReplaceAll(text, "\u00ad\n", "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+type Generation struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	MainRegion     NamedAPIResource   `json:"main_region"`
+	PokemonSpecies []NamedAPIResource `json:"pokemon_species"`
+}
 
-	return &data, nil
-}
\ No newline at end of file
+type Region struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	Locations      []NamedAPIResource `json:"locations"`
+	MainGeneration NamedAPIResource   `json:"main_generation"`
+}
+
+type Location struct {
+	ID     int                `json:"id"`
+	Name   string             `json:"name"`
+	Region NamedAPIResource   `json:"region"`
+	Areas  []NamedAPIResource `json:"areas"`
+}
+
+// The numeric ID at the end of a resource URL such as
+// https://pokeapi.co/api/v2/pokemon-species/25/
+func IDFromURL(url string) (int, bool) {
+	trimmed := strings.TrimSuffix(url, "/")
+	id, err := strconv.Atoi(trimmed[strings.LastIndex(trimmed, "/")+1:])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,121 @@
+package pokeapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults chosen to stay well inside PokeAPI's fair use policy
+const DefaultRateLimit = 10.0
+const DefaultBurst = 10
+const DefaultMaxInFlight = 4
+
+// Limit requests to requestsPerSecond on average, allowing bursts of up to
+// burst requests. A rate of zero or less disables rate limiting.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.rateLimit = requestsPerSecond
+		c.burst = burst
+	}
+}
+
+// Cap the number of requests on the wire at once. Zero or less means no cap.
+func WithMaxInFlight(maxInFlight int) Option {
+	return func(c *Client) {
+		c.maxInFlight = maxInFlight
+	}
+}
+
+// limiter is shared by every request a Client makes, including retries
+type limiter struct {
+	// nil if not rate limiting
+	bucket *tokenBucket
+
+	// Holds a token per request in flight, nil if uncapped
+	inFlight chan struct{}
+}
+
+func newLimiter(requestsPerSecond float64, burst int, maxInFlight int) *limiter {
+	l := limiter{}
+	if requestsPerSecond > 0 {
+		l.bucket = newTokenBucket(requestsPerSecond, max(burst, 1))
+	}
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+	return &l
+}
+
+// Block until a request may be made, returning a func to call when it is done
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+			release = func() { <-l.inFlight }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if l.bucket != nil {
+		err := l.bucket.wait(ctx)
+		if err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+type tokenBucket struct {
+	// Must lock this before accessing tokens or last
+	mu sync.Mutex
+
+	// Tokens added per second, and the most the bucket holds
+	rate  float64
+	burst float64
+
+	// Goes negative when callers have reserved tokens they are waiting on
+	tokens float64
+
+	// When tokens was last topped up
+	last time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Take a token, waiting for one to accrue if the bucket is empty. Tokens are
+// reserved up front so waiters are served in the order they arrived.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	err := sleep(ctx, delay)
+	if err != nil {
+		// Hand back the reservation we never used
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketLimitsRate(t *testing.T) {
+	const rate = 200.0
+	bucket := newTokenBucket(rate, 1)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		err := bucket.wait(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The first token is free, the other 4 accrue at 5ms each
+	elapsed := time.Since(start)
+	if elapsed < 4*time.Second/rate {
+		t.Errorf("expected rate limiting, 5 tokens took %v", elapsed)
+	}
+}
+
+func TestTokenBucketCancellation(t *testing.T) {
+	bucket := newTokenBucket(0.001, 1)
+	bucket.wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	err := bucket.wait(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClientMaxInFlight(t *testing.T) {
+	const maxInFlight = 2
+	var current, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := current.Add(1)
+		for {
+			seen := peak.Load()
+			if now <= seen || peak.CompareAndSwap(seen, now) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		current.Add(-1)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(0, 0), WithMaxInFlight(maxInFlight))
+	defer client.Close()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetPokemonDetail(context.Background(), fmt.Sprint(i))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > maxInFlight {
+		t.Errorf("expected at most %v requests in flight, saw %v", maxInFlight, peak.Load())
+	}
+}
@@ -0,0 +1,77 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"github.com/venzy/pokedexcli/internal/pokeapi/fakeapi"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplayTransportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	recorder := newFakeClient(t, WithHTTPClient(&http.Client{Transport: &ReplayTransport{Dir: dir, Record: true}}))
+	recorded, err := recorder.GetPokemonDetail(ctx, "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error recording: %v", err)
+	}
+
+	golden, err := os.ReadFile(filepath.Join(dir, "api-v2-pokemon-pikachu.http"))
+	if err != nil {
+		t.Fatalf("expected golden file: %v", err)
+	}
+	if strings.Contains(string(golden), "\r\nDate:") {
+		t.Errorf("expected Date header to be stripped from golden file")
+	}
+
+	// The base URL needn't match the recording, nor point anywhere real
+	replayer := NewClient(
+		WithBaseURL("http://replay.invalid"+fakeapi.BasePath),
+		WithHTTPClient(&http.Client{Transport: &ReplayTransport{Dir: dir}}),
+	)
+	defer replayer.Close()
+	replayed, err := replayer.GetPokemonDetail(ctx, "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error replaying: %v", err)
+	}
+	if replayed.Name != recorded.Name || replayed.BaseExperience != recorded.BaseExperience {
+		t.Errorf("replayed %v %v, recorded %v %v", replayed.Name, replayed.BaseExperience, recorded.Name, recorded.BaseExperience)
+	}
+
+	_, err = replayer.GetPokemonDetail(ctx, "bulbasaur")
+	if err == nil || !strings.Contains(err.Error(), "no recording") {
+		t.Errorf("expected missing recording error, got %v", err)
+	}
+}
+
+func TestReplayTransportRecordsErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, record := range []bool{true, false} {
+		client := newFakeClient(t, WithHTTPClient(&http.Client{Transport: &ReplayTransport{Dir: dir, Record: record}}))
+		_, err := client.GetPokemonDetail(context.Background(), "missingno")
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) {
+			t.Errorf("record %v: expected NotFoundError, got %v", record, err)
+		}
+	}
+}
+
+func TestGoldenName(t *testing.T) {
+	cases := map[string]string{
+		"https://pokeapi.co/api/v2/location-area":                        "api-v2-location-area.http",
+		"http://127.0.0.1:1234/api/v2/location-area/?offset=20&limit=20": "api-v2-location-area-offset=20-limit=20.http",
+		"https://pokeapi.co/api/v2/pokemon/mr-mime/":                     "api-v2-pokemon-mr-mime.http",
+	}
+	for url, expected := range cases {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if name := GoldenName(req); name != expected {
+			t.Errorf("%s: expected %s, got %s", url, expected, name)
+		}
+	}
+}
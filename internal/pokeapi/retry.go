@@ -0,0 +1,87 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how transient failures (network errors, 5xx and 429
+// responses) are retried. Only GETs are ever issued, so retrying is safe.
+type RetryPolicy struct {
+	// Total attempts including the first, so 1 disables retries
+	MaxAttempts int
+
+	// Backoff before the first retry, doubling for each one after that
+	BaseDelay time.Duration
+
+	// Upper bound on any wait. A server's Retry-After is honoured up to this,
+	// but asking for longer gives up and returns the RateLimitedError.
+	MaxDelay time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// How long to wait after the given (1-based) failed attempt, or false if the
+// server wants us to wait longer than MaxDelay
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	var rateLimited *RateLimitedError
+	if errors.As(err, &rateLimited) && rateLimited.RetryAfter > 0 {
+		return rateLimited.RetryAfter, rateLimited.RetryAfter <= p.MaxDelay
+	}
+
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff > p.MaxDelay || backoff <= 0 {
+		backoff = p.MaxDelay
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+	// "Full jitter", so that many clients failing together don't retry together
+	return time.Duration(rand.Int63n(int64(backoff) + 1)), true
+}
+
+// Whether err is worth another attempt
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		// Our caller gave up, as opposed to a timeout on a single attempt
+		return false
+	}
+
+	var rateLimited *RateLimitedError
+	var serverError *ServerError
+	var notFound *NotFoundError
+	var statusError *StatusError
+	switch {
+	case errors.As(err, &rateLimited), errors.As(err, &serverError):
+		return true
+	case errors.As(err, &notFound), errors.As(err, &statusError):
+		return false
+	default:
+		// Anything else came from the transport, e.g. a reset connection
+		return true
+	}
+}
+
+// Wait out a retry delay, returning early if ctx is cancelled
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
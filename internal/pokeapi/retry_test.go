@@ -0,0 +1,104 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientRetriesTransientFailures(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch requests.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"name": "pikachu"}`))
+		}
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(policy))
+	defer client.Close()
+	detail, err := client.GetPokemonDetail(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if detail.Name != "pikachu" {
+		t.Errorf("expected pikachu, got %v", detail.Name)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("expected 3 requests, got %v", requests.Load())
+	}
+}
+
+func TestClientGivesUpAfterMaxAttempts(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(policy))
+	defer client.Close()
+	_, err := client.GetPokemonDetail(context.Background(), "pikachu")
+	var serverError *ServerError
+	if !errors.As(err, &serverError) {
+		t.Errorf("expected ServerError, got %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("expected 2 requests, got %v", requests.Load())
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	for attempt, limit := range []time.Duration{100, 200, 300, 300} {
+		limit *= time.Millisecond
+		delay, ok := policy.delay(attempt+1, &ServerError{})
+		if !ok || delay < 0 || delay > limit {
+			t.Errorf("attempt %d: expected delay up to %v, got %v", attempt+1, limit, delay)
+		}
+	}
+
+	delay, ok := policy.delay(1, &RateLimitedError{RetryAfter: 200 * time.Millisecond})
+	if !ok || delay != 200*time.Millisecond {
+		t.Errorf("expected Retry-After to be honoured, got %v %v", delay, ok)
+	}
+	if _, ok := policy.delay(1, &RateLimitedError{RetryAfter: 2 * time.Second}); ok {
+		t.Errorf("expected Retry-After beyond MaxDelay to give up")
+	}
+}
+
+func TestClientGivesUpOnLongRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+	start := time.Now()
+	_, err := client.GetPokemonDetail(context.Background(), "pikachu")
+	var rateLimited *RateLimitedError
+	if !errors.As(err, &rateLimited) || rateLimited.RetryAfter != time.Hour {
+		t.Errorf("expected RateLimitedError, got %v", err)
+	}
+	if requests.Load() != 1 || time.Since(start) > DefaultRetryPolicy.MaxDelay {
+		t.Errorf("expected to give up at once, got %v requests in %v", requests.Load(), time.Since(start))
+	}
+}
@@ -0,0 +1,71 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"github.com/venzy/pokedexcli/internal/pokecache"
+	"sync"
+)
+
+// flightGroup collapses concurrent fetches of the same URL into a single
+// fetch whose result is shared by every caller waiting on it.
+type flightGroup struct {
+	// Must lock this before accessing flights
+	mu sync.Mutex
+
+	// Fetches currently in progress, keyed on URL
+	flights map[string]*flight
+}
+
+type flight struct {
+	// Closed once val and err are set
+	done chan struct{}
+	val pokecache.Entry
+	err error
+}
+
+// Run fn for key, unless another caller is already doing so in which case
+// wait for and share its result.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() (pokecache.Entry, error)) (pokecache.Entry, error) {
+	for {
+		g.mu.Lock()
+		if g.flights == nil {
+			g.flights = make(map[string]*flight)
+		}
+		existing, ok := g.flights[key]
+		if !ok {
+			break
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-existing.done:
+		case <-ctx.Done():
+			return pokecache.Entry{}, ctx.Err()
+		}
+		// If the leader was cancelled that says nothing about our own request,
+		// so go round again (most likely as the new leader)
+		if isContextError(existing.err) && ctx.Err() == nil {
+			continue
+		}
+		return existing.val, existing.err
+	}
+
+	// Still holding the lock from the loop above
+	current := &flight{done: make(chan struct{})}
+	g.flights[key] = current
+	g.mu.Unlock()
+
+	current.val, current.err = fn()
+
+	g.mu.Lock()
+	delete(g.flights, key)
+	g.mu.Unlock()
+	close(current.done)
+
+	return current.val, current.err
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
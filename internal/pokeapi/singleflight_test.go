@@ -0,0 +1,107 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"github.com/venzy/pokedexcli/internal/pokecache"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientCoalescesConcurrentMisses(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		// Give every caller a chance to pile up behind this request
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			detail, err := client.GetPokemonDetail(context.Background(), "pikachu")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if detail.Name != "pikachu" {
+				t.Errorf("expected pikachu, got %v", detail.Name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %v", requests.Load())
+	}
+}
+
+func TestFlightGroupSurvivesCancelledLeader(t *testing.T) {
+	var group flightGroup
+	leaderStarted := make(chan struct{})
+	releaseLeader := make(chan struct{})
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+
+	leaderErr := make(chan error)
+	go func() {
+		_, err := group.do(leaderCtx, "key", func() (pokecache.Entry, error) {
+			close(leaderStarted)
+			<-releaseLeader
+			return pokecache.Entry{}, leaderCtx.Err()
+		})
+		leaderErr <- err
+	}()
+	<-leaderStarted
+
+	var followerFetches atomic.Int32
+	followerCtx := &waitingContext{Context: context.Background(), waiting: make(chan struct{})}
+	result := make(chan []byte)
+	go func() {
+		entry, err := group.do(followerCtx, "key", func() (pokecache.Entry, error) {
+			followerFetches.Add(1)
+			return pokecache.Entry{Val: []byte("follower")}, nil
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		result <- entry.Val
+	}()
+
+	// Only let the leader finish once the follower is waiting on it
+	<-followerCtx.waiting
+	cancelLeader()
+	close(releaseLeader)
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected leader to be cancelled, got %v", err)
+	}
+	if val := <-result; string(val) != "follower" {
+		t.Errorf("expected follower to fetch for itself, got %q", val)
+	}
+	if followerFetches.Load() != 1 {
+		t.Errorf("expected follower to fetch once, got %v", followerFetches.Load())
+	}
+}
+
+// Signals waiting the first time Done is called, which flightGroup.do only
+// does once it has joined another caller's flight
+type waitingContext struct {
+	context.Context
+	waiting chan struct{}
+	once    sync.Once
+}
+
+func (c *waitingContext) Done() <-chan struct{} {
+	c.once.Do(func() { close(c.waiting) })
+	return c.Context.Done()
+}
@@ -0,0 +1,46 @@
+package pokeapi
+
+// PokemonSpecies is what Pokémon varieties have in common, e.g. pikachu and
+// pikachu-rock-star are both varieties of the pikachu species
+type PokemonSpecies struct {
+	ID                 int                `json:"id"`
+	Name               string             `json:"name"`
+	Order              int                `json:"order"`
+	Names              []Name             `json:"names"`
+	Genera             []Genus            `json:"genera"`
+	FlavorTextEntries  []FlavorText       `json:"flavor_text_entries"`
+	CaptureRate        int                `json:"capture_rate"`
+	BaseHappiness      int                `json:"base_happiness"`
+	GenderRate         int                `json:"gender_rate"`
+	HatchCounter       int                `json:"hatch_counter"`
+	IsBaby             bool               `json:"is_baby"`
+	IsLegendary        bool               `json:"is_legendary"`
+	IsMythical         bool               `json:"is_mythical"`
+	EggGroups          []NamedAPIResource `json:"egg_groups"`
+	GrowthRate         NamedAPIResource   `json:"growth_rate"`
+	Generation         NamedAPIResource   `json:"generation"`
+	EvolvesFromSpecies *NamedAPIResource  `json:"evolves_from_species"`
+	EvolutionChain     struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	Varieties []struct {
+		IsDefault bool             `json:"is_default"`
+		Pokemon   NamedAPIResource `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+// Genus is a species' category in one language, e.g. "Mouse Pokémon"
+type Genus struct {
+	Genus    string           `json:"genus"`
+	Language NamedAPIResource `json:"language"`
+}
+
+// The genus in the given language, e.g. "en" or "ja"
+func (s *PokemonSpecies) LocalizedGenus(language string) (string, bool) {
+	for _, genus := range s.Genera {
+		if genus.Language.Name == language {
+			return genus.Genus, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,63 @@
+package pokeapi
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetPokemonSpecies(t *testing.T) {
+	client := newFakeClient(t)
+
+	species, err := client.GetPokemonSpecies(context.Background(), "25")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if species.Name != "pikachu" || species.CaptureRate != 190 || species.GrowthRate.Name != "medium" {
+		t.Errorf("unexpected species: %v %v %v", species.Name, species.CaptureRate, species.GrowthRate.Name)
+	}
+	if genus, ok := species.LocalizedGenus("ja"); !ok || genus != "ねずみポケモン" {
+		t.Errorf("unexpected ja genus: %q %v", genus, ok)
+	}
+	if name, ok := LocalizedName(species.Names, "en"); !ok || name != "Pikachu" {
+		t.Errorf("unexpected en name: %q %v", name, ok)
+	}
+	if _, ok := LocalizedName(species.Names, "fr"); ok {
+		t.Errorf("expected no fr name")
+	}
+}
+
+func TestLocalizedFlavorText(t *testing.T) {
+	en := NamedAPIResource{Name: "en"}
+	ja := NamedAPIResource{Name: "ja"}
+	entries := []FlavorText{
+		{"red en", en, NamedAPIResource{Name: "red"}},
+		{"red ja", ja, NamedAPIResource{Name: "red"}},
+		{"yellow en", en, NamedAPIResource{Name: "yellow"}},
+	}
+
+	cases := []struct {
+		language, version, expected string
+		ok                          bool
+	}{
+		{"en", "red", "red en", true},
+		{"ja", "red", "red ja", true},
+		{"en", "", "yellow en", true},
+		{"ja", "", "red ja", true},
+		{"ja", "yellow", "", false},
+		{"fr", "", "", false},
+	}
+	for _, c := range cases {
+		entry, ok := LocalizedFlavorText(entries, c.language, c.version)
+		if ok != c.ok || entry.FlavorText != c.expected {
+			t.Errorf("%s/%s: expected %q %v, got %q %v", c.language, c.version, c.expected, c.ok, entry.FlavorText, ok)
+		}
+	}
+}
+
+func TestCleanFlavorText(t *testing.T) {
+	raw := "It keeps its tail\nraised to monitor\nits surroundings.\fIf you yank its\ntail, it will try\nto bite you. Light­\nning!"
+	expected := "It keeps its tail raised to monitor its surroundings. If you yank its tail, it will try to bite you. Lightning!"
+	if cleaned := CleanFlavorText(raw); cleaned != expected {
+		t.Errorf("expected %q, got %q", expected, cleaned)
+	}
+}
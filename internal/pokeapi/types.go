@@ -0,0 +1,122 @@
+package pokeapi
+
+import (
+	"sort"
+)
+
+// Type is an elemental type such as fire, with how it fares against others
+type Type struct {
+	ID              int                 `json:"id"`
+	Name            string              `json:"name"`
+	Names           []Name              `json:"names"`
+	DamageRelations TypeDamageRelations `json:"damage_relations"`
+	Pokemon         []struct {
+		Slot    int              `json:"slot"`
+		Pokemon NamedAPIResource `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+// TypeDamageRelations lists the types a type is unusually effective against
+// (to) or takes unusual damage from (from). Anything unlisted is 1x.
+type TypeDamageRelations struct {
+	NoDamageTo       []NamedAPIResource `json:"no_damage_to"`
+	HalfDamageTo     []NamedAPIResource `json:"half_damage_to"`
+	DoubleDamageTo   []NamedAPIResource `json:"double_damage_to"`
+	NoDamageFrom     []NamedAPIResource `json:"no_damage_from"`
+	HalfDamageFrom   []NamedAPIResource `json:"half_damage_from"`
+	DoubleDamageFrom []NamedAPIResource `json:"double_damage_from"`
+}
+
+func (r *TypeDamageRelations) empty() bool {
+	return len(r.NoDamageTo)+len(r.HalfDamageTo)+len(r.DoubleDamageTo)+
+		len(r.NoDamageFrom)+len(r.HalfDamageFrom)+len(r.DoubleDamageFrom) == 0
+}
+
+// TypeChart holds the damage multiplier for every attacking and defending
+// pair of types
+type TypeChart struct {
+	// Attacking type, then defending type. Missing pairs are 1x.
+	multipliers map[string]map[string]float64
+
+	// Sorted
+	types []string
+}
+
+// Build a chart from types' damage relations. Types with no relations at
+// all, such as unknown or stellar, are left out of the chart.
+func NewTypeChart(types []*Type) *TypeChart {
+	chart := TypeChart{multipliers: make(map[string]map[string]float64)}
+	for _, t := range types {
+		if t.DamageRelations.empty() {
+			continue
+		}
+		chart.types = append(chart.types, t.Name)
+		relations := &t.DamageRelations
+		// Both directions are recorded, so a chart built from only some of the
+		// types is still right for those types
+		chart.set(t.Name, relations.NoDamageTo, 0)
+		chart.set(t.Name, relations.HalfDamageTo, 0.5)
+		chart.set(t.Name, relations.DoubleDamageTo, 2)
+		chart.setFrom(relations.NoDamageFrom, t.Name, 0)
+		chart.setFrom(relations.HalfDamageFrom, t.Name, 0.5)
+		chart.setFrom(relations.DoubleDamageFrom, t.Name, 2)
+	}
+	sort.Strings(chart.types)
+	return &chart
+}
+
+func (c *TypeChart) set(attacking string, defending []NamedAPIResource, multiplier float64) {
+	for _, d := range defending {
+		c.setPair(attacking, d.Name, multiplier)
+	}
+}
+
+func (c *TypeChart) setFrom(attacking []NamedAPIResource, defending string, multiplier float64) {
+	for _, a := range attacking {
+		c.setPair(a.Name, defending, multiplier)
+	}
+}
+
+func (c *TypeChart) setPair(attacking string, defending string, multiplier float64) {
+	if c.multipliers[attacking] == nil {
+		c.multipliers[attacking] = make(map[string]float64)
+	}
+	c.multipliers[attacking][defending] = multiplier
+}
+
+// The types in the chart, sorted by name
+func (c *TypeChart) Types() []string {
+	return c.types
+}
+
+// Damage multiplier for a move of the attacking type against a Pokémon with
+// all of the defending types, e.g. 4 for ground against electric and steel
+func (c *TypeChart) Effectiveness(attacking string, defending ...string) float64 {
+	multiplier := 1.0
+	for _, d := range defending {
+		if m, ok := c.multipliers[attacking][d]; ok {
+			multiplier *= m
+		}
+	}
+	return multiplier
+}
+
+// Damage multiplier for moves of every type in the chart against a Pokémon
+// with all of the defending types
+func (c *TypeChart) Defending(defending ...string) map[string]float64 {
+	multipliers := make(map[string]float64, len(c.types))
+	for _, attacking := range c.types {
+		multipliers[attacking] = c.Effectiveness(attacking, defending...)
+	}
+	return multipliers
+}
+
+// Damage multiplier for moves of the attacking type against each single type
+// in the chart
+func (c *TypeChart) Attacking(attacking string) map[string]float64 {
+	multipliers := make(map[string]float64, len(c.types))
+	for _, defending := range c.types {
+		multipliers[defending] = c.Effectiveness(attacking, defending)
+	}
+	return multipliers
+}
@@ -0,0 +1,55 @@
+package pokeapi
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTypeChart(t *testing.T) {
+	client := newFakeClient(t, WithRateLimit(0, 0))
+
+	chart, err := client.GetTypeChart(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// stellar has no damage relations so is left out
+	if len(chart.Types()) != 18 {
+		t.Errorf("expected 18 types, got %d: %v", len(chart.Types()), chart.Types())
+	}
+
+	cases := []struct {
+		attacking string
+		defending []string
+		expected  float64
+	}{
+		{"fire", []string{"grass"}, 2},
+		{"water", []string{"fire"}, 2},
+		{"electric", []string{"ground"}, 0},
+		{"normal", []string{"normal"}, 1},
+		{"ground", []string{"electric", "steel"}, 4},
+		{"grass", []string{"grass", "poison"}, 0.25},
+		{"ground", []string{"flying", "electric"}, 0},
+		{"ice", []string{"water", "ground"}, 1},
+	}
+	for _, c := range cases {
+		if m := chart.Effectiveness(c.attacking, c.defending...); m != c.expected {
+			t.Errorf("%s against %v: expected %gx, got %gx", c.attacking, c.defending, c.expected, m)
+		}
+	}
+}
+
+func TestTypeChartFromSomeTypes(t *testing.T) {
+	// Relations in both directions are recorded, so a chart of only fire
+	// still knows water is super effective against it
+	fire := &Type{Name: "fire", DamageRelations: TypeDamageRelations{
+		DoubleDamageTo:   []NamedAPIResource{{Name: "grass"}},
+		DoubleDamageFrom: []NamedAPIResource{{Name: "water"}},
+	}}
+	chart := NewTypeChart([]*Type{fire})
+	if m := chart.Effectiveness("water", "fire"); m != 2 {
+		t.Errorf("expected 2x, got %gx", m)
+	}
+	if m := chart.Defending("fire")["fire"]; m != 1 {
+		t.Errorf("expected 1x, got %gx", m)
+	}
+}
@@ -0,0 +1,149 @@
+package pokecache
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Archives are JSON lines: a header identifying the format, then one line
+// per entry. They can be gzipped by the caller for transport.
+const archiveFormat = "pokedexcli-cache"
+const archiveVersion = 1
+
+// Allow for large response bodies, which are base64 encoded on a single line
+const maxArchiveLine = 64 << 20
+
+type archiveHeader struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+type archiveEntry struct {
+	Key          string    `json:"key"`
+	CreatedAt    time.Time `json:"created_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+
+	// Hex SHA-256 of Body, so corruption is caught on import
+	SHA256 string `json:"sha256"`
+	Body   []byte `json:"body"`
+}
+
+// Write every entry in store to w, returning how many were written. Entries
+// are read with Peek where the store has it, so exporting a Cache neither
+// reorders its LRU nor counts towards its Stats.
+func Export(store Store, w io.Writer) (int, error) {
+	read := store.GetEntry
+	if peeker, ok := store.(interface{ Peek(string) (Entry, bool) }); ok {
+		read = peeker.Peek
+	}
+
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(archiveHeader{archiveFormat, archiveVersion})
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for _, key := range store.Keys() {
+		entry, ok := read(key)
+		if !ok {
+			// Went away since we listed the keys
+			continue
+		}
+		sum := sha256.Sum256(entry.Val)
+		err := encoder.Encode(archiveEntry{
+			Key:          key,
+			CreatedAt:    entry.CreatedAt,
+			ETag:         entry.ETag,
+			LastModified: entry.LastModified,
+			ExpiresAt:    entry.ExpiresAt,
+			SHA256:       hex.EncodeToString(sum[:]),
+			Body:         entry.Val,
+		})
+		if err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+// Add every entry in an archive written by Export to store, returning how
+// many were added. The whole archive is read and checked before anything is
+// added, so a corrupt or truncated archive leaves store untouched.
+func Import(store Store, r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxArchiveLine)
+
+	if !scanner.Scan() {
+		if scanner.Err() != nil {
+			return 0, scanner.Err()
+		}
+		return 0, errors.New("empty cache archive")
+	}
+	var header archiveHeader
+	err := json.Unmarshal(scanner.Bytes(), &header)
+	if err != nil || header.Format != archiveFormat {
+		return 0, errors.New("not a cache archive")
+	}
+	if header.Version != archiveVersion {
+		return 0, fmt.Errorf("unsupported cache archive version %d", header.Version)
+	}
+
+	type keyedEntry struct {
+		key   string
+		entry Entry
+	}
+	var staged []keyedEntry
+	for line := 2; scanner.Scan(); line++ {
+		var entry archiveEntry
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		err = entry.validate()
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		staged = append(staged, keyedEntry{entry.Key, Entry{
+			Val:       entry.Body,
+			CreatedAt: entry.CreatedAt,
+			Metadata: Metadata{
+				ETag:         entry.ETag,
+				LastModified: entry.LastModified,
+				ExpiresAt:    entry.ExpiresAt,
+			},
+		}})
+	}
+	if scanner.Err() != nil {
+		return 0, scanner.Err()
+	}
+
+	for _, staged := range staged {
+		store.AddEntry(staged.key, staged.entry)
+	}
+	return len(staged), nil
+}
+
+func (e *archiveEntry) validate() error {
+	if e.Key == "" {
+		return errors.New("entry has no key")
+	}
+	if e.CreatedAt.IsZero() {
+		return fmt.Errorf("entry for %s has no creation time", e.Key)
+	}
+	sum := sha256.Sum256(e.Body)
+	if hex.EncodeToString(sum[:]) != e.SHA256 {
+		return fmt.Errorf("entry for %s is corrupt, checksum mismatch", e.Key)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package pokecache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	source := NewFileStore(t.TempDir())
+	expiresAt := time.Now().Add(time.Hour).Round(0)
+	source.AddEntry("https://example.com/pokemon/pikachu", Entry{
+		Val:      []byte(`{"name": "pikachu"}`),
+		Metadata: Metadata{ETag: `"v1"`, LastModified: "Wed, 01 Jan 2025 00:00:00 GMT", ExpiresAt: expiresAt},
+	})
+	source.AddEntry("https://example.com/pokemon/raichu", Entry{Val: []byte(`{"name": "raichu"}`)})
+
+	var archive bytes.Buffer
+	exported, err := Export(source, &archive)
+	if err != nil || exported != 2 {
+		t.Fatalf("expected 2 entries exported, got %v: %v", exported, err)
+	}
+
+	destination := NewCache(5 * time.Second)
+	defer destination.Close()
+	imported, err := Import(destination, &archive)
+	if err != nil || imported != 2 {
+		t.Fatalf("expected 2 entries imported, got %v: %v", imported, err)
+	}
+
+	original, _ := source.GetEntry("https://example.com/pokemon/pikachu")
+	entry, ok := destination.GetEntry("https://example.com/pokemon/pikachu")
+	if !ok {
+		t.Fatalf("expected to find imported key")
+	}
+	if string(entry.Val) != `{"name": "pikachu"}` ||
+		entry.ETag != original.ETag ||
+		entry.LastModified != original.LastModified ||
+		!entry.ExpiresAt.Equal(original.ExpiresAt) ||
+		!entry.CreatedAt.Equal(original.CreatedAt) {
+		t.Errorf("expected entry to survive intact, got %q %+v", entry.Val, entry)
+	}
+}
+
+func TestArchiveValidation(t *testing.T) {
+	header := `{"format": "pokedexcli-cache", "version": 1}` + "\n"
+	cases := []struct {
+		name    string
+		archive string
+		err     string
+	}{
+		{
+			name:    "not an archive",
+			archive: `{"hello": "world"}` + "\n",
+			err:     "not a cache archive",
+		},
+		{
+			name:    "future version",
+			archive: `{"format": "pokedexcli-cache", "version": 2}` + "\n",
+			err:     "unsupported cache archive version 2",
+		},
+		{
+			name:    "missing key",
+			archive: header + `{"created_at": "2025-01-01T00:00:00Z", "sha256": "", "body": ""}` + "\n",
+			err:     "line 2: entry has no key",
+		},
+		{
+			name: "truncated",
+			// A valid entry, then one cut off mid-line
+			archive: header + `{"key": "https://example.com/a", "created_at": "2025-01-01T00:00:00Z", "sha256": "810ff2fb242a5dee4220f2cb0e6a519891fb67f2f828a6cab4ef8894633b1f50", "body": "dGVzdGRhdGE="}` + "\n" +
+				`{"key": "https://example.com/b", "created_at": "2025-01-01T00:00:00Z", "sha256": "81`,
+			err: "line 3:",
+		},
+		{
+			name: "checksum mismatch",
+			// Body is "testdata", checksum is of something else
+			archive: header + `{"key": "https://example.com", "created_at": "2025-01-01T00:00:00Z", "sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "body": "dGVzdGRhdGE="}` + "\n",
+			err:     "line 2: entry for https://example.com is corrupt",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store := NewFileStore(t.TempDir())
+			_, err := Import(store, strings.NewReader(c.archive))
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("expected error containing %q, got %v", c.err, err)
+			}
+			if keys := store.Keys(); len(keys) != 0 {
+				t.Errorf("expected nothing imported, got %v", keys)
+			}
+		})
+	}
+}
+
+func TestExportLeavesCacheUntouched(t *testing.T) {
+	dir := t.TempDir()
+	NewFileStore(dir).AddEntry("https://example.com/pokemon/raichu", Entry{Val: []byte("raichu")})
+	cache := NewCache(5*time.Second, WithDir(dir))
+	defer cache.Close()
+	cache.Add("https://example.com/pokemon/pikachu", []byte("pikachu"))
+	before := cache.Stats()
+
+	exported, err := Export(cache, &bytes.Buffer{})
+	if err != nil || exported != 2 {
+		t.Fatalf("expected 2 entries exported, got %v: %v", exported, err)
+	}
+
+	after := cache.Stats()
+	if after.Hits != before.Hits || after.DiskHits != before.DiskHits || after.Misses != before.Misses {
+		t.Errorf("expected export not to count towards stats, got %+v then %+v", before, after)
+	}
+	if after.Entries != 1 {
+		t.Errorf("expected disk entries not to be promoted, got %v in memory", after.Entries)
+	}
+}
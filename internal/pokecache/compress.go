@@ -0,0 +1,50 @@
+package pokecache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// Values are stored either as given (an empty encoding) or gzipped
+const encodingGzip = "gzip"
+
+// Store values gzip-compressed, in memory and on disk. Compression is
+// transparent to callers of Get and Add, and is skipped for any value it
+// doesn't make smaller.
+func WithCompression(enabled bool) Option {
+	return func(c *Cache) {
+		c.compress = enabled
+	}
+}
+
+// Returns the bytes to store along with their encoding
+func encode(val []byte) ([]byte, string) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(val)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil || buf.Len() >= len(val) {
+		return val, ""
+	}
+	return buf.Bytes(), encodingGzip
+}
+
+func decode(stored []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return stored, nil
+	case encodingGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(stored))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	default:
+		return nil, fmt.Errorf("unknown cache entry encoding %q", encoding)
+	}
+}
@@ -0,0 +1,61 @@
+package pokecache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestCompressionIsTransparent(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(5*time.Second, WithCompression(true), WithDir(dir))
+	defer cache.Close()
+
+	body := bytes.Repeat([]byte(`{"name": "pikachu", "url": "https://pokeapi.co/api/v2/pokemon/25/"}`), 100)
+	cache.Add("https://example.com/pokemon/pikachu", body)
+	// Too small to benefit, so should be kept as is
+	cache.Add("https://example.com/tiny", []byte("x"))
+
+	val, ok := cache.Get("https://example.com/pokemon/pikachu")
+	if !ok || !bytes.Equal(val, body) {
+		t.Errorf("expected to get back the uncompressed value")
+	}
+	val, ok = cache.Get("https://example.com/tiny")
+	if !ok || string(val) != "x" {
+		t.Errorf("expected to get back the tiny value")
+	}
+
+	stats := cache.Stats()
+	if stats.Bytes >= stats.RawBytes {
+		t.Errorf("expected stored bytes below raw bytes, got %v and %v", stats.Bytes, stats.RawBytes)
+	}
+
+	// Compressed entries must also come back intact from disk, whether or
+	// not the reading cache compresses
+	reopened := NewCache(5*time.Second, WithDir(dir))
+	defer reopened.Close()
+	val, ok = reopened.Get("https://example.com/pokemon/pikachu")
+	if !ok || !bytes.Equal(val, body) {
+		t.Errorf("expected to get back the uncompressed value from disk")
+	}
+}
+
+func TestFileStoreCompression(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir, WithFileCompression(true))
+	body := bytes.Repeat([]byte(`{"name": "pikachu", "url": "https://pokeapi.co/api/v2/pokemon/25/"}`), 100)
+	store.AddEntry("https://example.com/pokemon/pikachu", Entry{Val: body})
+
+	stored, ok, err := store.get("https://example.com/pokemon/pikachu")
+	if err != nil || !ok {
+		t.Fatalf("expected to find key on disk: %v", err)
+	}
+	if stored.Encoding != encodingGzip || len(stored.Val) >= len(body) {
+		t.Errorf("expected a compressed file, got encoding %q and %v bytes", stored.Encoding, len(stored.Val))
+	}
+
+	entry, ok := NewFileStore(dir).GetEntry("https://example.com/pokemon/pikachu")
+	if !ok || !bytes.Equal(entry.Val, body) {
+		t.Errorf("expected to get back the uncompressed value")
+	}
+}
@@ -0,0 +1,255 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Directory name used under the user's cache dir
+const appDirName = "pokedexcli"
+
+// DefaultDir is where the persistent cache lives unless told otherwise,
+// e.g. ~/.cache/pokedexcli on Linux
+func DefaultDir() (string, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(userCacheDir, appDirName), nil
+}
+
+// FileStore keeps one gob-encoded file per entry, named by a hash of the key
+// so that arbitrary URLs are safe to use as keys. It can be used as a Store
+// in its own right, or as the persistent tier behind a Cache (see WithDir).
+type FileStore struct {
+	dir string
+
+	// Whether AddEntry gzips values. A Cache compresses before writing
+	// through, so this only matters for a FileStore used on its own.
+	compress bool
+}
+
+type FileStoreOption func(*FileStore)
+
+// Store values gzip-compressed, as WithCompression does for a Cache
+func WithFileCompression(enabled bool) FileStoreOption {
+	return func(d *FileStore) {
+		d.compress = enabled
+	}
+}
+
+// Kept flat rather than embedding Entry so gob stays tolerant of new fields
+type diskEntry struct {
+	Key          string
+	CreatedAt    time.Time
+	Val          []byte
+	ETag         string
+	LastModified string
+	ExpiresAt    time.Time
+
+	// How Val is encoded, see compress.go
+	Encoding string
+}
+
+// Val is taken from stored rather than entry, as it may be compressed
+func newDiskEntry(key string, entry Entry, stored []byte, encoding string) diskEntry {
+	return diskEntry{
+		Key:          key,
+		CreatedAt:    entry.CreatedAt,
+		Val:          stored,
+		ETag:         entry.ETag,
+		LastModified: entry.LastModified,
+		ExpiresAt:    entry.ExpiresAt,
+		Encoding:     encoding,
+	}
+}
+
+// The entry as callers see it, with its decoded value
+func (d diskEntry) entry(val []byte) Entry {
+	return Entry{
+		Val:       val,
+		CreatedAt: d.CreatedAt,
+		Metadata: Metadata{
+			ETag:         d.ETag,
+			LastModified: d.LastModified,
+			ExpiresAt:    d.ExpiresAt,
+		},
+	}
+}
+
+func NewFileStore(dir string, options ...FileStoreOption) *FileStore {
+	store := &FileStore{dir: dir}
+	for _, option := range options {
+		option(store)
+	}
+	return store
+}
+
+func (d *FileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:]))
+}
+
+func (d *FileStore) get(key string) (diskEntry, bool, error) {
+	file, err := os.Open(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return diskEntry{}, false, nil
+	} else if err != nil {
+		return diskEntry{}, false, err
+	}
+	defer file.Close()
+
+	var entry diskEntry
+	err = gob.NewDecoder(file).Decode(&entry)
+	if err != nil {
+		return diskEntry{}, false, err
+	}
+	// Guard against the (vanishingly unlikely) hash collision
+	if entry.Key != key {
+		return diskEntry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+func (d *FileStore) add(entry diskEntry) error {
+	err := os.MkdirAll(d.dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file then rename, so readers never see a partial entry
+	file, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	err = gob.NewEncoder(file).Encode(entry)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), d.path(entry.Key))
+}
+
+func (d *FileStore) delete(key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Names of the files holding entries, skipping temp files and anything else
+// that may have found its way into the directory
+func (d *FileStore) files() ([]string, error) {
+	dirEntries, err := os.ReadDir(d.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		_, err := hex.DecodeString(name)
+		if dirEntry.Type().IsRegular() && len(name) == sha256.Size*2 && err == nil {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// All keys on disk. This has to read every file, so isn't cheap.
+func (d *FileStore) keys() ([]string, error) {
+	names, err := d.files()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for _, name := range names {
+		file, err := os.Open(filepath.Join(d.dir, name))
+		if err != nil {
+			return nil, err
+		}
+		var entry diskEntry
+		err = gob.NewDecoder(file).Decode(&entry)
+		file.Close()
+		if err != nil {
+			// Skip anything corrupt rather than failing the whole listing
+			continue
+		}
+		keys = append(keys, entry.Key)
+	}
+	return keys, nil
+}
+
+func (d *FileStore) GetEntry(key string) (Entry, bool) {
+	stored, ok, err := d.get(key)
+	if err != nil && debug {
+		fmt.Printf("File store read failed for %s: %v\n", key, err)
+	}
+	if !ok {
+		return Entry{}, false
+	}
+	val, err := decode(stored.Val, stored.Encoding)
+	if err != nil {
+		if debug {
+			fmt.Printf("File store decode failed for %s: %v\n", key, err)
+		}
+		return Entry{}, false
+	}
+	return stored.entry(val), true
+}
+
+func (d *FileStore) Meta(key string) (Entry, bool) {
+	stored, ok, err := d.get(key)
+	if err != nil && debug {
+		fmt.Printf("File store read failed for %s: %v\n", key, err)
+	}
+	if !ok {
+		return Entry{}, false
+	}
+	return stored.entry(nil), true
+}
+
+// Entries are written as given bar compression, CreatedAt defaults to now
+func (d *FileStore) AddEntry(key string, entry Entry) {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	stored, encoding := entry.Val, ""
+	if d.compress {
+		stored, encoding = encode(entry.Val)
+	}
+	err := d.add(newDiskEntry(key, entry, stored, encoding))
+	if err != nil && debug {
+		fmt.Printf("File store write failed for %s: %v\n", key, err)
+	}
+}
+
+func (d *FileStore) Delete(key string) {
+	err := d.delete(key)
+	if err != nil && debug {
+		fmt.Printf("File store delete failed for %s: %v\n", key, err)
+	}
+}
+
+func (d *FileStore) Keys() []string {
+	keys, err := d.keys()
+	if err != nil && debug {
+		fmt.Printf("File store listing failed: %v\n", err)
+	}
+	return keys
+}
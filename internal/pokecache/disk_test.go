@@ -0,0 +1,50 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiskSurvivesNewCache(t *testing.T) {
+	dir := t.TempDir()
+	first := NewCache(5*time.Second, WithDir(dir))
+	defer first.Close()
+	first.Add("https://example.com/pokemon/pikachu", []byte("pikachu"))
+
+	second := NewCache(5*time.Second, WithDir(dir))
+	defer second.Close()
+	val, ok := second.Get("https://example.com/pokemon/pikachu")
+	if !ok {
+		t.Fatalf("expected to find key on disk")
+	}
+	if string(val) != "pikachu" {
+		t.Errorf("expected %q, got %q", "pikachu", val)
+	}
+
+	_, ok = second.Get("https://example.com/pokemon/raichu")
+	if ok {
+		t.Errorf("expected to not find key")
+	}
+}
+
+func TestDiskSurvivesReap(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	const waitTime = baseTime + 5*time.Millisecond
+	cache := NewCache(baseTime, WithDir(t.TempDir()))
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+
+	time.Sleep(waitTime)
+
+	cache.mu.Lock()
+	_, inMemory := cache.data["https://example.com"]
+	cache.mu.Unlock()
+	if inMemory {
+		t.Errorf("expected key to be reaped from memory")
+	}
+
+	val, ok := cache.Get("https://example.com")
+	if !ok || string(val) != "testdata" {
+		t.Errorf("expected to find key on disk")
+	}
+}
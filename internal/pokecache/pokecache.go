@@ -1,6 +1,7 @@
 package pokecache
 
 import (
+	"container/list"
 	"fmt"
 	"time"
 	"sync"
@@ -9,71 +10,361 @@ import (
 var debug bool = false
 
 type Cache struct {
-	// Must lock this before accessing data
-	mu sync.RWMutex
+	// Must lock this before accessing data, recency or size. Even reads
+	// take the full lock as they update recency.
+	mu sync.Mutex
 
-	// Cached byte data keyed on a string
-	data map[string]cacheEntry
+	// Cached byte data keyed on a string, elements hold a *cacheEntry
+	data map[string]*list.Element
+
+	// Entries ordered from most to least recently used
+	recency *list.List
+
+	// Bytes held in memory, counting both keys and values
+	size int64
+
+	// Running totals reported by Stats
+	counters counters
+
+	// Limits on the in-memory layer, zero means unbounded
+	maxBytes   int64
+	maxEntries int
+
+	// Whether to gzip values before storing them
+	compress bool
+
+	// Optional persistent tier behind the in-memory map, nil if memory only
+	disk *FileStore
+
+	// Closed to ask the reap loop to stop, which closes reaped on its way out
+	done      chan struct{}
+	reaped    chan struct{}
+	closeOnce sync.Once
+}
+
+type Option func(*Cache)
+
+// Persist entries under dir so they survive restarts. The in-memory map
+// becomes a hot layer in front of it, and reaping only affects that layer.
+func WithDir(dir string) Option {
+	return func(c *Cache) {
+		c.disk = NewFileStore(dir)
+	}
+}
+
+// Evict least recently used entries from memory once they take up more than
+// maxBytes. Entries persisted to disk are unaffected.
+func WithMaxBytes(maxBytes int64) Option {
+	return func(c *Cache) {
+		c.maxBytes = maxBytes
+	}
+}
+
+// Evict least recently used entries from memory once there are more than
+// maxEntries. Entries persisted to disk are unaffected.
+func WithMaxEntries(maxEntries int) Option {
+	return func(c *Cache) {
+		c.maxEntries = maxEntries
+	}
 }
 
 type cacheEntry struct {
+	key string
+
 	// When the entry was created
 	createdAt time.Time
 
-	// TODO if we're really nice we'd store the "cache-control: max-age"
-	// response header value here and use that where possible
+	// When the entry entered the in-memory layer, used for reaping entries
+	// that don't carry their own expiry
+	addedAt time.Time
+
+	// HTTP caching information supplied by the caller
+	meta Metadata
 
-	// The raw data we're cachine
-	val []byte
+	// The data we're cachine, encoded as per encoding
+	val      []byte
+	encoding string
+
+	// Length of val before encoding
+	rawSize int64
+}
+
+// Bytes held in memory, which is what the limits apply to
+func (e *cacheEntry) size() int64 {
+	return int64(len(e.key) + len(e.val))
 }
 
-func NewCache(interval time.Duration) *Cache {
+func (e *cacheEntry) rawBytes() int64 {
+	return int64(len(e.key)) + e.rawSize
+}
+
+// Metadata is the HTTP caching information recorded alongside a value
+type Metadata struct {
+	// Validators for conditional requests, empty if the server sent none
+	ETag         string
+	LastModified string
+
+	// When the entry goes stale. Zero means the server gave no freshness
+	// information, so the entry lives until the cache's reap interval passes.
+	ExpiresAt time.Time
+}
+
+// Whether a stale entry can be refreshed with a conditional request rather
+// than refetched in full. Entries without an expiry are fresh until reaped,
+// so never need revalidating.
+func (m Metadata) revalidatable() bool {
+	return !m.ExpiresAt.IsZero() && (m.ETag != "" || m.LastModified != "")
+}
+
+// Entry is a cached value along with its metadata
+type Entry struct {
+	Val       []byte
+	CreatedAt time.Time
+	Metadata
+}
+
+// Whether the entry can be used without revalidating it
+func (e Entry) Fresh(now time.Time) bool {
+	return e.ExpiresAt.IsZero() || now.Before(e.ExpiresAt)
+}
+
+func NewCache(interval time.Duration, options ...Option) *Cache {
 	cache := Cache{}
-	cache.data = make(map[string]cacheEntry)
-	
+	cache.data = make(map[string]*list.Element)
+	cache.recency = list.New()
+	cache.done = make(chan struct{})
+	cache.reaped = make(chan struct{})
+	for _, option := range options {
+		option(&cache)
+	}
+
 	go cache.reapLoop(interval)
 
 	return &cache
 }
 
+// Stop reaping and release the reap goroutine, waiting for it to exit. The
+// cache remains usable afterwards, but entries are no longer expired.
+func (c *Cache) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	<-c.reaped
+}
+
 // Must run this inside a go func
 func (c *Cache) reapLoop(interval time.Duration) {
-	// This will run forever, we don't bother with a shutdown channel yet.
+	defer close(c.reaped)
 	reapTicker := time.NewTicker(interval)
-	for now := range reapTicker.C {
-		c.mu.Lock()
+	defer reapTicker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case now := <-reapTicker.C:
+			c.reap(now, interval)
+		}
+	}
+}
+
+// Drop expired entries from memory. Stale entries with validators are kept
+// so they can be revalidated with a conditional request, and are left for
+// the LRU limits to evict.
+func (c *Cache) reap(now time.Time, interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-		for key, entry := range c.data {
-			expiry := entry.createdAt.Add(interval)
-			if expiry.Before(now) {
-				if debug {
-					fmt.Printf("Expiring cache entry for %s\n", key)
-				}
-				delete(c.data, key)
+	for key, elem := range c.data {
+		entry := elem.Value.(*cacheEntry)
+		if entry.meta.revalidatable() {
+			continue
+		}
+		expiry := entry.meta.ExpiresAt
+		if expiry.IsZero() {
+			expiry = entry.addedAt.Add(interval)
+		}
+		if expiry.Before(now) {
+			if debug {
+				fmt.Printf("Expiring cache entry for %s\n", key)
 			}
+			c.remove(elem)
+			c.counters.expirations++
 		}
-
-		c.mu.Unlock()
 	}
 }
 
 func (c *Cache) Add(key string, val []byte) {
+	c.AddEntry(key, Entry{Val: val})
+}
+
+// Add a value with its HTTP caching metadata, CreatedAt defaults to now
+func (c *Cache) AddEntry(key string, entry Entry) {
+	now := time.Now()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = now
+	}
+	stored, encoding := entry.Val, ""
+	if c.compress {
+		stored, encoding = encode(entry.Val)
+	}
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.store(&cacheEntry{
+		key:       key,
+		createdAt: entry.CreatedAt,
+		addedAt:   now,
+		meta:      entry.Metadata,
+		val:       stored,
+		encoding:  encoding,
+		rawSize:   int64(len(entry.Val)),
+	})
+	c.mu.Unlock()
 
-	c.data[key] = cacheEntry{time.Now(), val}
+	if c.disk != nil {
+		// The disk tier is best effort, a failed write just means a future miss
+		err := c.disk.add(newDiskEntry(key, entry, stored, encoding))
+		if err != nil && debug {
+			fmt.Printf("Cache disk write failed for %s: %v\n", key, err)
+		}
+	}
 }
 
 func (c *Cache) Get(key string) ([]byte, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	entry, ok := c.GetEntry(key)
+	return entry.Val, ok
+}
+
+// Like GetEntry, but without promoting disk entries into memory, reordering
+// the LRU or counting towards Stats, for reads that aren't real use such as
+// exporting the cache
+func (c *Cache) Peek(key string) (Entry, bool) {
+	c.mu.Lock()
+	elem, ok := c.data[key]
+	var entry cacheEntry
+	if ok {
+		entry = *elem.Value.(*cacheEntry)
+	}
+	c.mu.Unlock()
 
-	entry, ok := c.data[key]
 	if !ok {
-		return nil, false
+		if c.disk == nil {
+			return Entry{}, false
+		}
+		return c.disk.GetEntry(key)
+	}
+	val, err := decode(entry.val, entry.encoding)
+	if err != nil {
+		if debug {
+			fmt.Printf("Cache decode failed for %s: %v\n", key, err)
+		}
+		return Entry{}, false
+	}
+	return Entry{val, entry.createdAt, entry.meta}, true
+}
+
+// Get a value with its metadata, whether or not it is still fresh
+func (c *Cache) GetEntry(key string) (Entry, bool) {
+	c.mu.Lock()
+	elem, ok := c.data[key]
+	if ok {
+		c.recency.MoveToFront(elem)
+		c.counters.hits++
+	} else if c.disk == nil {
+		c.counters.misses++
+	}
+	c.mu.Unlock()
+
+	if ok {
+		if debug {
+			fmt.Printf("Cache HIT for %s\n", key)
+		}
+		entry := elem.Value.(*cacheEntry)
+		val, err := decode(entry.val, entry.encoding)
+		if err != nil {
+			if debug {
+				fmt.Printf("Cache decode failed for %s: %v\n", key, err)
+			}
+			return Entry{}, false
+		}
+		return Entry{val, entry.createdAt, entry.meta}, true
+	}
+
+	if c.disk == nil {
+		return Entry{}, false
+	}
+	stored, ok, err := c.disk.get(key)
+	if err != nil && debug {
+		fmt.Printf("Cache disk read failed for %s: %v\n", key, err)
+	}
+	if !ok {
+		c.mu.Lock()
+		c.counters.misses++
+		c.mu.Unlock()
+		return Entry{}, false
 	}
 	if debug {
-		fmt.Printf("Cache HIT for %s\n", key)
+		fmt.Printf("Cache disk HIT for %s\n", key)
+	}
+
+	val, err := decode(stored.Val, stored.Encoding)
+	if err != nil {
+		if debug {
+			fmt.Printf("Cache decode failed for %s: %v\n", key, err)
+		}
+		c.mu.Lock()
+		c.counters.misses++
+		c.mu.Unlock()
+		return Entry{}, false
+	}
+
+	// Promote into the hot layer, still encoded
+	found := stored.entry(val)
+	c.mu.Lock()
+	c.store(&cacheEntry{
+		key:       key,
+		createdAt: found.CreatedAt,
+		addedAt:   time.Now(),
+		meta:      found.Metadata,
+		val:       stored.Val,
+		encoding:  stored.Encoding,
+		rawSize:   int64(len(val)),
+	})
+	c.counters.diskHits++
+	c.mu.Unlock()
+
+	return found, true
+}
+
+// Insert or replace an entry as the most recently used, then evict down to
+// the configured limits. Must hold c.mu.
+func (c *Cache) store(entry *cacheEntry) {
+	if elem, ok := c.data[entry.key]; ok {
+		c.remove(elem)
+	}
+	c.data[entry.key] = c.recency.PushFront(entry)
+	c.size += entry.size()
+
+	for c.overLimit() {
+		oldest := c.recency.Back()
+		if debug {
+			fmt.Printf("Evicting cache entry for %s\n", oldest.Value.(*cacheEntry).key)
+		}
+		c.remove(oldest)
+		c.counters.evictions++
 	}
-	return entry.val, true
-}
\ No newline at end of file
+}
+
+// Must hold c.mu
+func (c *Cache) overLimit() bool {
+	if c.recency.Len() == 0 {
+		return false
+	}
+	return (c.maxBytes > 0 && c.size > c.maxBytes) ||
+		(c.maxEntries > 0 && c.recency.Len() > c.maxEntries)
+}
+
+// Must hold c.mu
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.recency.Remove(elem).(*cacheEntry)
+	delete(c.data, entry.key)
+	c.size -= entry.size()
+}
@@ -2,6 +2,7 @@ package pokecache
 
 import (
 	"fmt"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -25,6 +26,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := NewCache(interval)
+			defer cache.Close()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -43,6 +45,7 @@ func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
 	cache := NewCache(baseTime)
+	defer cache.Close()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -58,4 +61,136 @@ func TestReapLoop(t *testing.T) {
 		t.Errorf("expected to not find key")
 		return
 	}
-}
\ No newline at end of file
+}
+func TestReapLoopHonoursEntryExpiry(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	const waitTime = baseTime + 5*time.Millisecond
+	cache := NewCache(baseTime)
+	defer cache.Close()
+	cache.AddEntry("https://example.com/short", Entry{
+		Val:      []byte("short"),
+		Metadata: Metadata{ExpiresAt: time.Now()},
+	})
+	cache.AddEntry("https://example.com/long", Entry{
+		Val:      []byte("long"),
+		Metadata: Metadata{ETag: `"v1"`, ExpiresAt: time.Now().Add(time.Hour)},
+	})
+
+	time.Sleep(waitTime)
+
+	_, ok := cache.Get("https://example.com/short")
+	if ok {
+		t.Errorf("expected expired key to be reaped")
+	}
+	entry, ok := cache.GetEntry("https://example.com/long")
+	if !ok {
+		t.Fatalf("expected unexpired key to outlive the reap interval")
+	}
+	if entry.ETag != `"v1"` || !entry.Fresh(time.Now()) {
+		t.Errorf("expected metadata to be kept, got %+v", entry.Metadata)
+	}
+}
+
+func TestReapKeepsRevalidatableEntries(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	cache := NewCache(baseTime)
+	defer cache.Close()
+	cache.AddEntry("https://example.com/etag", Entry{
+		Val:      []byte("etag"),
+		Metadata: Metadata{ETag: `"v1"`, ExpiresAt: time.Now()},
+	})
+	cache.AddEntry("https://example.com/plain", Entry{
+		Val:      []byte("plain"),
+		Metadata: Metadata{ExpiresAt: time.Now()},
+	})
+
+	cache.reap(time.Now().Add(time.Hour), baseTime)
+
+	entry, ok := cache.GetEntry("https://example.com/etag")
+	if !ok || entry.ETag != `"v1"` {
+		t.Errorf("expected stale entry with a validator to be kept, got %+v %v", entry.Metadata, ok)
+	}
+	if entry.Fresh(time.Now()) {
+		t.Errorf("expected kept entry to still be stale")
+	}
+	if _, ok := cache.GetEntry("https://example.com/plain"); ok {
+		t.Errorf("expected stale entry without validators to be reaped")
+	}
+}
+
+func TestLRUEvictsByEntryCount(t *testing.T) {
+	cache := NewCache(5*time.Second, WithMaxEntries(2))
+	defer cache.Close()
+	cache.Add("a", []byte("1"))
+	cache.Add("b", []byte("2"))
+	// Touch "a" so that "b" becomes the least recently used
+	cache.Get("a")
+	cache.Add("c", []byte("3"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected least recently used key to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected to find key %s", key)
+		}
+	}
+}
+
+func TestLRUEvictsByBytes(t *testing.T) {
+	// Each entry is a 1 byte key plus a 10 byte value
+	cache := NewCache(5*time.Second, WithMaxBytes(25))
+	defer cache.Close()
+	for _, key := range []string{"a", "b", "c"} {
+		cache.Add(key, []byte("0123456789"))
+	}
+
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("expected oldest key to be evicted")
+	}
+	cache.mu.Lock()
+	size := cache.size
+	cache.mu.Unlock()
+	if size != 22 {
+		t.Errorf("expected 22 bytes tracked, got %v", size)
+	}
+
+	// Replacing a value must not double count it
+	cache.Add("c", []byte("01234"))
+	cache.mu.Lock()
+	size = cache.size
+	cache.mu.Unlock()
+	if size != 17 {
+		t.Errorf("expected 17 bytes tracked, got %v", size)
+	}
+}
+
+func TestCloseStopsReapLoop(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	caches := []*Cache{}
+	for i := 0; i < 10; i++ {
+		caches = append(caches, NewCache(time.Millisecond))
+	}
+	for _, cache := range caches {
+		cache.Close()
+	}
+	// Closing twice is harmless
+	caches[0].Close()
+
+	// Goroutines may take a moment to be fully torn down after signalling
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected no leaked goroutines, had %v now %v", before, after)
+	}
+
+	// Still usable, just no longer reaped
+	caches[0].Add("https://example.com", []byte("testdata"))
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := caches[0].Get("https://example.com"); !ok {
+		t.Errorf("expected to find key after Close")
+	}
+}
@@ -0,0 +1,157 @@
+package pokecache
+
+import (
+	"strings"
+	"time"
+)
+
+type counters struct {
+	hits        int64
+	diskHits    int64
+	misses      int64
+	evictions   int64
+	expirations int64
+}
+
+// Stats is a snapshot of cache activity since it was created
+type Stats struct {
+	// Lookups served from memory, served from disk, and not served at all
+	Hits     int64
+	DiskHits int64
+	Misses   int64
+
+	// Entries dropped from memory to stay within limits, and because they expired
+	Evictions   int64
+	Expirations int64
+
+	// What's held in memory right now. Bytes is what is actually stored,
+	// RawBytes what that would be without compression.
+	Entries  int
+	Bytes    int64
+	RawBytes int64
+
+	// Ages of the oldest and newest entries in memory, zero if empty
+	OldestAge time.Duration
+	NewestAge time.Duration
+
+	// Entries in the persistent tier, -1 if there isn't one
+	DiskEntries int
+}
+
+// EntryInfo describes a single entry held in memory
+type EntryInfo struct {
+	Key       string
+	Bytes     int64
+	RawBytes  int64
+	Age       time.Duration
+	ExpiresAt time.Time
+}
+
+func (c *Cache) Stats() Stats {
+	now := time.Now()
+	c.mu.Lock()
+	stats := Stats{
+		Hits:        c.counters.hits,
+		DiskHits:    c.counters.diskHits,
+		Misses:      c.counters.misses,
+		Evictions:   c.counters.evictions,
+		Expirations: c.counters.expirations,
+		Entries:     c.recency.Len(),
+		Bytes:       c.size,
+		DiskEntries: -1,
+	}
+	for elem := c.recency.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*cacheEntry)
+		stats.RawBytes += entry.rawBytes()
+		age := now.Sub(entry.createdAt)
+		if age > stats.OldestAge {
+			stats.OldestAge = age
+		}
+		if stats.NewestAge == 0 || age < stats.NewestAge {
+			stats.NewestAge = age
+		}
+	}
+	c.mu.Unlock()
+
+	if c.disk != nil {
+		files, err := c.disk.files()
+		if err == nil {
+			stats.DiskEntries = len(files)
+		}
+	}
+	return stats
+}
+
+// Count a hit for key that was served from above the cache, e.g. from values
+// the caller decoded earlier and checked against Meta, so Stats still show
+// the cache being used
+func (c *Cache) RecordHit(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.data[key]; ok {
+		c.recency.MoveToFront(elem)
+		c.counters.hits++
+	} else if c.disk != nil {
+		c.counters.diskHits++
+	}
+}
+
+// Entries held in memory, most recently used first
+func (c *Cache) Entries() []EntryInfo {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	infos := []EntryInfo{}
+	for elem := c.recency.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*cacheEntry)
+		infos = append(infos, EntryInfo{
+			Key:       entry.key,
+			Bytes:     entry.size(),
+			RawBytes:  entry.rawBytes(),
+			Age:       now.Sub(entry.createdAt),
+			ExpiresAt: entry.meta.ExpiresAt,
+		})
+	}
+	return infos
+}
+
+// Remove every entry, from disk as well as memory
+func (c *Cache) Clear() error {
+	_, err := c.Purge("")
+	return err
+}
+
+// Remove every entry whose key starts with prefix, from disk as well as
+// memory, returning how many distinct keys were removed
+func (c *Cache) Purge(prefix string) (int, error) {
+	removed := map[string]bool{}
+
+	c.mu.Lock()
+	for key, elem := range c.data {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(elem)
+			removed[key] = true
+		}
+	}
+	c.mu.Unlock()
+
+	if c.disk != nil {
+		keys, err := c.disk.keys()
+		if err != nil {
+			return len(removed), err
+		}
+		for _, key := range keys {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			err := c.disk.delete(key)
+			if err != nil {
+				return len(removed), err
+			}
+			removed[key] = true
+		}
+	}
+
+	return len(removed), nil
+}
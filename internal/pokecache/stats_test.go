@@ -0,0 +1,81 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsCountsLookups(t *testing.T) {
+	cache := NewCache(5*time.Second, WithMaxEntries(1))
+	defer cache.Close()
+
+	cache.Add("https://example.com/a", []byte("aaaa"))
+	cache.Get("https://example.com/a")
+	cache.Get("https://example.com/missing")
+	cache.Add("https://example.com/b", []byte("bb"))
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Evictions != 1 {
+		t.Errorf("expected 1 hit, 1 miss and 1 eviction, got %+v", stats)
+	}
+	if stats.Entries != 1 || stats.Bytes != int64(len("https://example.com/b")+2) {
+		t.Errorf("expected 1 entry of %v bytes, got %+v", len("https://example.com/b")+2, stats)
+	}
+	if stats.DiskEntries != -1 {
+		t.Errorf("expected no disk tier, got %v", stats.DiskEntries)
+	}
+}
+
+func TestRecordHit(t *testing.T) {
+	cache := NewCache(5 * time.Second)
+	defer cache.Close()
+	cache.Add("https://example.com/a", []byte("aaaa"))
+
+	if _, ok := cache.Meta("https://example.com/a"); !ok {
+		t.Fatalf("expected to find key")
+	}
+	if stats := cache.Stats(); stats.Hits != 0 {
+		t.Errorf("expected Meta not to count, got %+v", stats)
+	}
+	cache.RecordHit("https://example.com/a")
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 0 {
+		t.Errorf("expected 1 hit, got %+v", stats)
+	}
+}
+
+func TestPurgeAndClear(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(5*time.Second, WithDir(dir))
+	defer cache.Close()
+
+	cache.Add("https://example.com/pokemon/pikachu", []byte("pikachu"))
+	cache.Add("https://example.com/pokemon/raichu", []byte("raichu"))
+	cache.Add("https://example.com/location-area/1", []byte("area"))
+
+	removed, err := cache.Purge("https://example.com/pokemon/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("expected 2 entries purged, got %v", removed)
+	}
+
+	// Purged entries must be gone from disk too
+	reopened := NewCache(5*time.Second, WithDir(dir))
+	defer reopened.Close()
+	if _, ok := reopened.Get("https://example.com/pokemon/pikachu"); ok {
+		t.Errorf("expected purged key to be gone from disk")
+	}
+	if _, ok := reopened.Get("https://example.com/location-area/1"); !ok {
+		t.Errorf("expected unpurged key to remain")
+	}
+
+	err = cache.Clear()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stats := cache.Stats()
+	if stats.Entries != 0 || stats.DiskEntries != 0 {
+		t.Errorf("expected empty cache after clear, got %+v", stats)
+	}
+}
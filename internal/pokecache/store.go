@@ -0,0 +1,111 @@
+package pokecache
+
+import (
+	"sort"
+	"strings"
+)
+
+// Store is somewhere to keep cache entries. Methods work on whole entries
+// (value plus metadata) hence the Entry suffix, leaving Cache's Get and Add
+// for plain byte values.
+type Store interface {
+	// Get an entry whether or not it is still fresh
+	GetEntry(key string) (Entry, bool)
+	// Like GetEntry but leaves Val nil, sparing a decompress when only the
+	// metadata is wanted. Doesn't count towards Cache.Stats, see RecordHit.
+	Meta(key string) (Entry, bool)
+	AddEntry(key string, entry Entry)
+	Delete(key string)
+	Keys() []string
+}
+
+var _ Store = (*Cache)(nil)
+var _ Store = (*FileStore)(nil)
+var _ Store = NopStore{}
+
+// NopStore never holds anything, for running without a cache at all
+type NopStore struct{}
+
+func (NopStore) GetEntry(key string) (Entry, bool) {
+	return Entry{}, false
+}
+
+func (NopStore) Meta(key string) (Entry, bool) {
+	return Entry{}, false
+}
+
+func (NopStore) AddEntry(key string, entry Entry) {}
+
+func (NopStore) Delete(key string) {}
+
+func (NopStore) Keys() []string {
+	return nil
+}
+
+// Remove every entry from store whose key starts with prefix, returning how
+// many were removed. Use Cache.Purge where possible, as it reports errors.
+func PurgeStore(store Store, prefix string) int {
+	removed := 0
+	for _, key := range store.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			store.Delete(key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Memory hits still count as a use for the LRU, but disk hits aren't
+// promoted, as the caller may not go on to want the value
+func (c *Cache) Meta(key string) (Entry, bool) {
+	c.mu.Lock()
+	elem, ok := c.data[key]
+	if ok {
+		c.recency.MoveToFront(elem)
+		entry := elem.Value.(*cacheEntry)
+		c.mu.Unlock()
+		return Entry{CreatedAt: entry.createdAt, Metadata: entry.meta}, true
+	}
+	c.mu.Unlock()
+
+	if c.disk == nil {
+		return Entry{}, false
+	}
+	return c.disk.Meta(key)
+}
+
+// Remove an entry from memory and disk
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	if elem, ok := c.data[key]; ok {
+		c.remove(elem)
+	}
+	c.mu.Unlock()
+
+	if c.disk != nil {
+		c.disk.Delete(key)
+	}
+}
+
+// Keys held in memory or on disk, sorted
+func (c *Cache) Keys() []string {
+	seen := map[string]bool{}
+	c.mu.Lock()
+	for key := range c.data {
+		seen[key] = true
+	}
+	c.mu.Unlock()
+
+	if c.disk != nil {
+		for _, key := range c.disk.Keys() {
+			seen[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStores(t *testing.T) {
+	memory := NewCache(5 * time.Second)
+	defer memory.Close()
+	tiered := NewCache(5*time.Second, WithDir(t.TempDir()), WithCompression(true))
+	defer tiered.Close()
+
+	stores := map[string]Store{
+		"memory": memory,
+		"tiered": tiered,
+		"file":   NewFileStore(t.TempDir()),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			expiresAt := time.Now().Add(time.Hour).Round(0)
+			store.AddEntry("https://example.com/pokemon/pikachu", Entry{
+				Val:      []byte("pikachu"),
+				Metadata: Metadata{ETag: `"v1"`, ExpiresAt: expiresAt},
+			})
+			store.AddEntry("https://example.com/pokemon/raichu", Entry{Val: []byte("raichu")})
+
+			entry, ok := store.GetEntry("https://example.com/pokemon/pikachu")
+			if !ok {
+				t.Fatalf("expected to find key")
+			}
+			if string(entry.Val) != "pikachu" || entry.ETag != `"v1"` || !entry.ExpiresAt.Equal(expiresAt) {
+				t.Errorf("unexpected entry: %q %+v", entry.Val, entry.Metadata)
+			}
+			if entry.CreatedAt.IsZero() {
+				t.Errorf("expected CreatedAt to default to now")
+			}
+
+			meta, ok := store.Meta("https://example.com/pokemon/pikachu")
+			if !ok || meta.Val != nil || meta.ETag != `"v1"` || !meta.CreatedAt.Equal(entry.CreatedAt) {
+				t.Errorf("unexpected metadata: %q %v %+v", meta.Val, meta.CreatedAt, meta.Metadata)
+			}
+			if _, ok := store.Meta("https://example.com/pokemon/bulbasaur"); ok {
+				t.Errorf("expected no metadata for a missing key")
+			}
+
+			keys := store.Keys()
+			if len(keys) != 2 {
+				t.Errorf("expected 2 keys, got %v", keys)
+			}
+
+			store.Delete("https://example.com/pokemon/pikachu")
+			if _, ok := store.GetEntry("https://example.com/pokemon/pikachu"); ok {
+				t.Errorf("expected deleted key to be gone")
+			}
+
+			if removed := PurgeStore(store, "https://example.com/"); removed != 1 {
+				t.Errorf("expected 1 key purged, got %v", removed)
+			}
+			if keys := store.Keys(); len(keys) != 0 {
+				t.Errorf("expected no keys left, got %v", keys)
+			}
+		})
+	}
+}
+
+func TestNopStore(t *testing.T) {
+	var store Store = NopStore{}
+	store.AddEntry("https://example.com", Entry{Val: []byte("testdata")})
+	if _, ok := store.GetEntry("https://example.com"); ok {
+		t.Errorf("expected NopStore to hold nothing")
+	}
+	if _, ok := store.Meta("https://example.com"); ok {
+		t.Errorf("expected NopStore to hold no metadata")
+	}
+}
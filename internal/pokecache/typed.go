@@ -0,0 +1,86 @@
+package pokecache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// TypedCache holds values decoded from entries in a byte Cache, so repeated
+// lookups can skip decoding. Each value is tagged with the CreatedAt of the
+// entry it was decoded from, and is only returned while that still matches,
+// so refreshing the underlying entry invalidates the decoded copy.
+type TypedCache[T any] struct {
+	// Must lock this before accessing data or recency
+	mu sync.Mutex
+
+	// Elements hold a *typedEntry[T]
+	data map[string]*list.Element
+
+	// Entries ordered from most to least recently used
+	recency *list.List
+
+	// Zero means unbounded
+	maxEntries int
+}
+
+type typedEntry[T any] struct {
+	key     string
+	version time.Time
+	val     T
+}
+
+func NewTypedCache[T any](maxEntries int) *TypedCache[T] {
+	return &TypedCache[T]{
+		data:       make(map[string]*list.Element),
+		recency:    list.New(),
+		maxEntries: maxEntries,
+	}
+}
+
+// Get the value for key if it was decoded from the given version of the entry
+func (c *TypedCache[T]) Get(key string, version time.Time) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.data[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	entry := elem.Value.(*typedEntry[T])
+	if !entry.version.Equal(version) {
+		// Decoded from bytes that have since been replaced
+		c.remove(elem)
+		var zero T
+		return zero, false
+	}
+	c.recency.MoveToFront(elem)
+	return entry.val, true
+}
+
+func (c *TypedCache[T]) Add(key string, version time.Time, val T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.data[key]; ok {
+		c.remove(elem)
+	}
+	c.data[key] = c.recency.PushFront(&typedEntry[T]{key, version, val})
+
+	for c.maxEntries > 0 && c.recency.Len() > c.maxEntries {
+		c.remove(c.recency.Back())
+	}
+}
+
+func (c *TypedCache[T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recency.Len()
+}
+
+// Must hold c.mu
+func (c *TypedCache[T]) remove(elem *list.Element) {
+	entry := c.recency.Remove(elem).(*typedEntry[T])
+	delete(c.data, entry.key)
+}
@@ -0,0 +1,47 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTypedCacheVersioning(t *testing.T) {
+	type pokemon struct {
+		name string
+	}
+	cache := NewTypedCache[*pokemon](0)
+	v1 := time.Now()
+	v2 := v1.Add(time.Second)
+
+	pikachu := &pokemon{"pikachu"}
+	cache.Add("https://example.com/pokemon/25", v1, pikachu)
+
+	val, ok := cache.Get("https://example.com/pokemon/25", v1)
+	if !ok || val != pikachu {
+		t.Errorf("expected to find the same value for the same version")
+	}
+
+	_, ok = cache.Get("https://example.com/pokemon/25", v2)
+	if ok {
+		t.Errorf("expected a newer version to invalidate the value")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("expected invalidated value to be dropped, have %v", cache.Len())
+	}
+}
+
+func TestTypedCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewTypedCache[int](2)
+	version := time.Now()
+	cache.Add("a", version, 1)
+	cache.Add("b", version, 2)
+	cache.Get("a", version)
+	cache.Add("c", version, 3)
+
+	if _, ok := cache.Get("b", version); ok {
+		t.Errorf("expected least recently used key to be evicted")
+	}
+	if val, ok := cache.Get("a", version); !ok || val != 1 {
+		t.Errorf("expected to find key a")
+	}
+}
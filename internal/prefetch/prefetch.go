@@ -0,0 +1,246 @@
+package prefetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"io"
+	"strconv"
+	"sync"
+)
+
+const DefaultWorkers = pokeapi.DefaultMaxInFlight
+
+// Prefetcher walks PokeAPI resources so that they end up in the client's
+// cache. As everything fetched is cached, running it again after an
+// interruption skips quickly past whatever was already done.
+type Prefetcher struct {
+	Client *pokeapi.Client
+
+	// How many resources to fetch at once, the client's own limits still apply
+	Workers int
+
+	// Where to report progress, nil for silence
+	Progress io.Writer
+}
+
+// Every location area and every Pokémon
+func (p *Prefetcher) All(ctx context.Context) error {
+	err := p.Areas(ctx)
+	if err != nil {
+		return err
+	}
+	return p.Pokemon(ctx)
+}
+
+func (p *Prefetcher) Areas(ctx context.Context) error {
+	names := []string{}
+	var pageUrl *string
+	for {
+		page, err := p.Client.GetLocationAreas(ctx, pageUrl)
+		if err != nil {
+			return err
+		}
+		for _, result := range page.Results {
+			names = append(names, result.Name)
+		}
+		p.printf("\rListing location areas: %d/%d", len(names), page.Count)
+		if page.Next == nil {
+			break
+		}
+		pageUrl = page.Next
+	}
+	p.printf("\n")
+
+	return p.fetchEach(ctx, "Location areas", names, func(ctx context.Context, name string) error {
+		_, err := p.Client.GetLocationAreaDetail(ctx, name)
+		return err
+	})
+}
+
+// Every Pokémon, plus the version groups moves needs to read their learnsets
+func (p *Prefetcher) Pokemon(ctx context.Context) error {
+	names := []string{}
+	var pageUrl *string
+	for {
+		page, err := p.Client.GetPokemonList(ctx, pageUrl)
+		if err != nil {
+			return err
+		}
+		for _, result := range page.Results {
+			names = append(names, result.Name)
+		}
+		p.printf("\rListing Pokémon: %d/%d", len(names), page.Count)
+		if page.Next == nil {
+			break
+		}
+		pageUrl = page.Next
+	}
+	p.printf("\n")
+
+	err := p.fetchEach(ctx, "Pokémon", names, func(ctx context.Context, name string) error {
+		_, err := p.Client.GetPokemonDetail(ctx, name)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return p.VersionGroups(ctx)
+}
+
+// Every version group, which the moves command needs to find a Pokémon's
+// latest learnset
+func (p *Prefetcher) VersionGroups(ctx context.Context) error {
+	names := []string{}
+	var pageUrl *string
+	for {
+		page, err := p.Client.GetVersionGroupList(ctx, pageUrl)
+		if err != nil {
+			return err
+		}
+		for _, result := range page.Results {
+			names = append(names, result.Name)
+		}
+		p.printf("\rListing version groups: %d/%d", len(names), page.Count)
+		if page.Next == nil {
+			break
+		}
+		pageUrl = page.Next
+	}
+	p.printf("\n")
+
+	return p.fetchEach(ctx, "Version groups", names, func(ctx context.Context, name string) error {
+		_, err := p.Client.GetVersionGroup(ctx, name)
+		return err
+	})
+}
+
+// The default form of every Pokémon species introduced in a generation,
+// given by name (e.g. generation-i) or number
+func (p *Prefetcher) Generation(ctx context.Context, generation string) error {
+	detail, err := p.Client.GetGeneration(ctx, generation)
+	if err != nil {
+		return err
+	}
+
+	// Species and their default Pokémon share an ID, but not always a name
+	ids := map[string]int{}
+	names := []string{}
+	for _, species := range detail.PokemonSpecies {
+		names = append(names, species.Name)
+		if id, ok := pokeapi.IDFromURL(species.URL); ok {
+			ids[species.Name] = id
+		}
+	}
+
+	err = p.fetchEach(ctx, detail.Name+" Pokémon", names, func(ctx context.Context, name string) error {
+		// Try by name first, as that's what users will look up
+		_, err := p.Client.GetPokemonDetail(ctx, name)
+		var notFound *pokeapi.NotFoundError
+		if id, ok := ids[name]; ok && errors.As(err, &notFound) {
+			_, err = p.Client.GetPokemonDetail(ctx, strconv.Itoa(id))
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return p.VersionGroups(ctx)
+}
+
+// Every location area in a region, e.g. kanto
+func (p *Prefetcher) Region(ctx context.Context, regionName string) error {
+	region, err := p.Client.GetRegion(ctx, regionName)
+	if err != nil {
+		return err
+	}
+
+	locationNames := []string{}
+	for _, location := range region.Locations {
+		locationNames = append(locationNames, location.Name)
+	}
+
+	var mu sync.Mutex
+	areaNames := []string{}
+	err = p.fetchEach(ctx, region.Name+" locations", locationNames, func(ctx context.Context, name string) error {
+		location, err := p.Client.GetLocation(ctx, name)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, area := range location.Areas {
+			areaNames = append(areaNames, area.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.fetchEach(ctx, region.Name+" location areas", areaNames, func(ctx context.Context, name string) error {
+		_, err := p.Client.GetLocationAreaDetail(ctx, name)
+		return err
+	})
+}
+
+// Run fetch for every name using a bounded pool of workers. Individual
+// failures are counted and reported at the end rather than stopping the
+// others, but cancelling ctx stops everything.
+func (p *Prefetcher) fetchEach(ctx context.Context, label string, names []string, fetch func(ctx context.Context, name string) error) error {
+	jobs := make(chan string)
+
+	// Must lock this before accessing the counters or printing progress
+	var mu sync.Mutex
+	done, failed := 0, 0
+	var firstErr error
+
+	var wg sync.WaitGroup
+	for i := 0; i < max(p.Workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				err := fetch(ctx, name)
+
+				mu.Lock()
+				if err != nil && ctx.Err() == nil {
+					failed++
+					if firstErr == nil {
+						firstErr = fmt.Errorf("%s: %w", name, err)
+					}
+				}
+				done++
+				p.printf("\r%s: %d/%d (%d failed)", label, done, len(names), failed)
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, name := range names {
+		select {
+		case jobs <- name:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	p.printf("\n")
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s failed, first error: %w", failed, len(names), label, firstErr)
+	}
+	return nil
+}
+
+func (p *Prefetcher) printf(format string, args ...any) {
+	if p.Progress != nil {
+		fmt.Fprintf(p.Progress, format, args...)
+	}
+}
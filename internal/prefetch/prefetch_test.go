@@ -0,0 +1,115 @@
+package prefetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"github.com/venzy/pokedexcli/internal/pokeapi/fakeapi"
+	"github.com/venzy/pokedexcli/internal/pokecache"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Serves a two page listing of areas, each of whose details are fetchable
+// apart from "broken-area"
+func newAreaServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Cache-Control", "max-age=3600")
+		switch {
+		case r.URL.Path == "/location-area" && r.URL.Query().Get("offset") == "":
+			fmt.Fprintf(w, `{"count": 3, "next": "%s/location-area?offset=2", "results": [{"name": "area-1"}, {"name": "area-2"}]}`, server.URL)
+		case r.URL.Path == "/location-area":
+			fmt.Fprintf(w, `{"count": 3, "previous": "%s/location-area", "results": [{"name": "broken-area"}]}`, server.URL)
+		case r.URL.Path == "/location-area/broken-area":
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasPrefix(r.URL.Path, "/location-area/"):
+			fmt.Fprintf(w, `{"name": "%s"}`, strings.TrimPrefix(r.URL.Path, "/location-area/"))
+		default:
+			t.Errorf("unexpected request for %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestAreasFetchesEverythingOnce(t *testing.T) {
+	var requests atomic.Int32
+	server := newAreaServer(t, &requests)
+	defer server.Close()
+
+	store := pokecache.NewFileStore(t.TempDir())
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL), pokeapi.WithStore(store))
+	defer client.Close()
+	prefetcher := Prefetcher{Client: client, Workers: 2}
+
+	err := prefetcher.Areas(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Errorf("expected 1 of 3 areas to fail, got %v", err)
+	}
+	for _, name := range []string{"area-1", "area-2"} {
+		if _, ok := store.GetEntry(server.URL + "/location-area/" + name); !ok {
+			t.Errorf("expected %s to be cached", name)
+		}
+	}
+
+	// Resuming only retries what wasn't cached, i.e. the broken area
+	before := requests.Load()
+	prefetcher.Areas(context.Background())
+	if retried := requests.Load() - before; retried != 1 {
+		t.Errorf("expected only the failed area to be refetched, got %v requests", retried)
+	}
+}
+
+func TestCancellationStopsPrefetch(t *testing.T) {
+	var requests atomic.Int32
+	server := newAreaServer(t, &requests)
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL), pokeapi.WithRateLimit(1, 1))
+	defer client.Close()
+	prefetcher := Prefetcher{Client: client, Workers: 1}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := prefetcher.Areas(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestPokemonIncludesVersionGroups(t *testing.T) {
+	server := httptest.NewServer(fakeapi.Default())
+	defer server.Close()
+	baseURL := server.URL + fakeapi.BasePath
+	store := pokecache.NewFileStore(t.TempDir())
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(baseURL), pokeapi.WithStore(store), pokeapi.WithRateLimit(0, 0))
+	defer client.Close()
+
+	err := (&Prefetcher{Client: client, Workers: 2}).Pokemon(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Everything moves needs must now be available offline
+	offline := pokeapi.NewClient(pokeapi.WithBaseURL(baseURL), pokeapi.WithStore(store), pokeapi.WithOffline(true))
+	defer offline.Close()
+	detail, err := offline.GetPokemonDetail(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, move := range detail.Moves {
+		for _, details := range move.VersionGroupDetails {
+			_, err := offline.GetVersionGroup(context.Background(), details.VersionGroup.Name)
+			if err != nil {
+				t.Errorf("expected %s to be cached: %v", details.VersionGroup.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Traps SIGINT so Ctrl-C cancels the running command rather than the whole REPL
+type interruptHandler struct {
+	// Must lock this before accessing cancel
+	mu sync.Mutex
+
+	// Cancels the in-flight command, nil while sitting at the prompt
+	cancel context.CancelFunc
+}
+
+func newInterruptHandler() *interruptHandler {
+	handler := interruptHandler{}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go handler.loop(signals)
+	return &handler
+}
+
+// Must run this inside a go func
+func (h *interruptHandler) loop(signals <-chan os.Signal) {
+	for range signals {
+		h.mu.Lock()
+		if h.cancel != nil {
+			h.cancel()
+		} else {
+			// Nothing to interrupt, so behave like a shell and start a fresh line
+			fmt.Print("\n" + prompt)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Context for a single command which Ctrl-C will cancel. Call the returned
+// func once the command has finished.
+func (h *interruptHandler) commandContext() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.mu.Lock()
+	h.cancel = cancel
+	h.mu.Unlock()
+
+	return ctx, func() {
+		h.mu.Lock()
+		h.cancel = nil
+		h.mu.Unlock()
+		cancel()
+	}
+}
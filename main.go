@@ -2,46 +2,149 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/venzy/pokedexcli/internal/commands"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"github.com/venzy/pokedexcli/internal/pokecache"
 	"os"
 	"strings"
 )
 
+const prompt = "Pokedex > "
+
 func main() {
-	commandContext := commands.NewContext()
+	debug := flag.Bool("debug", false, "Print debug output, e.g. retried requests")
+	retries := flag.Int("retries", pokeapi.DefaultRetryPolicy.MaxAttempts-1, "How many times to retry a failed request")
+	rate := flag.Float64("rate", pokeapi.DefaultRateLimit, "Maximum PokeAPI requests per second, 0 for no limit")
+	burst := flag.Int("burst", pokeapi.DefaultBurst, "How many requests may exceed -rate in a burst")
+	maxInFlight := flag.Int("max-in-flight", pokeapi.DefaultMaxInFlight, "Maximum concurrent PokeAPI requests, 0 for no limit")
+	cacheMaxBytes := flag.Int64("cache-max-bytes", 64<<20, "Memory to use for cached responses, 0 for no limit")
+	cacheMaxEntries := flag.Int("cache-max-entries", 0, "Maximum cached responses held in memory, 0 for no limit")
+	cacheCompress := flag.Bool("cache-compress", true, "Store cached responses gzip-compressed")
+	cacheBackend := flag.String("cache-backend", "tiered", "Where to cache responses: tiered (memory then disk), memory, file or none")
+	offline := flag.Bool("offline", false, "Never use the network, only serve what is already cached")
+	language := flag.String("lang", pokeapi.DefaultLanguage, "Language code for names and text, e.g. en or ja")
+	gameVersion := flag.String("game-version", "", "Game version to show Pokédex entries from, e.g. red, empty for the latest")
+	baseURL := flag.String("base-url", pokeapi.BaseURL, "PokeAPI to use, e.g. a local fakeapi server")
+	flag.Parse()
+
+	store, err := newStore(*cacheBackend, *cacheCompress,
+		pokecache.WithMaxBytes(*cacheMaxBytes),
+		pokecache.WithMaxEntries(*cacheMaxEntries),
+	)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if *offline {
+		if _, ok := store.(pokecache.NopStore); ok {
+			fmt.Println("-offline needs a cache to serve from, not -cache-backend none")
+			os.Exit(1)
+		}
+	}
+
+	retryPolicy := pokeapi.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = *retries + 1
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(*baseURL),
+		pokeapi.WithStore(store),
+		pokeapi.WithRetryPolicy(retryPolicy),
+		pokeapi.WithDebug(*debug),
+		pokeapi.WithRateLimit(*rate, *burst),
+		pokeapi.WithMaxInFlight(*maxInFlight),
+		pokeapi.WithOffline(*offline),
+	)
+
+	commandContext := commands.NewContext(client)
+	commandContext.Language = *language
+	commandContext.GameVersion = *gameVersion
+	interrupts := newInterruptHandler()
+
+	// Anything after the flags is a single command to run instead of the
+	// REPL, e.g. pokedexcli prefetch region kanto. The shell has already
+	// split the arguments, so quoted ones stay whole.
+	if flag.NArg() > 0 {
+		err := runCommand(interrupts, commandContext, flag.Args())
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	scanner := bufio.NewScanner(os.Stdin)
 	for {
-		fmt.Print("Pokedex > ")
+		fmt.Print(prompt)
 		if ! scanner.Scan() {
 			break
 		}
-		text := scanner.Text()
-		tokens := cleanInput(text)
+		tokens := strings.Fields(scanner.Text())
 		if len(tokens) == 0 {
 			continue
-		} else if len(tokens) > 1 {
-			commandContext.Arguments = tokens[1:]
 		}
-		command := tokens[0]
-		commandEntry, ok := (*commands.GetRegistry())[command]
-		if !ok {
-			fmt.Println("Unknown command")
-			continue
-		}
-		err := commandEntry.Callback(commandContext)
+		err := runCommand(interrupts, commandContext, tokens)
 		if err != nil {
 			fmt.Println(err)
 		}
 	}
 }
 
-func cleanInput(text string) []string {
-	if len(text) < 1 {
-		return []string{}
+// Look up and run the command named by the first token, with the rest as its
+// arguments. Ctrl-C interrupts just this command.
+func runCommand(interrupts *interruptHandler, commandContext *commands.CliCommandContext, tokens []string) error {
+	if len(tokens) == 0 || strings.TrimSpace(tokens[0]) == "" {
+		return errors.New("usage: pokedexcli [flags] <command> [arguments], see help for commands")
 	}
-	cleaned := strings.Fields(text)
-	for i, word := range cleaned {
+	cleaned := cleanInput(tokens)
+	// Always reset, so arguments don't leak into the next command
+	commandContext.Arguments = cleaned[1:]
+	commandContext.RawArguments = tokens[1:]
+	command := cleaned[0]
+	commandEntry, ok := (*commands.GetRegistry())[command]
+	if !ok {
+		return errors.New("Unknown command")
+	}
+
+	ctx, done := interrupts.commandContext()
+	defer done()
+	err := commandEntry.Callback(ctx, commandContext)
+	if errors.Is(err, context.Canceled) {
+		return errors.New("Interrupted")
+	}
+	return err
+}
+
+// Build the cache backend named on the command line. The default keeps a hot
+// in-memory layer in front of files under the user's cache dir.
+func newStore(backend string, compress bool, options ...pokecache.Option) (pokecache.Store, error) {
+	options = append(options, pokecache.WithCompression(compress))
+	switch backend {
+	case "tiered", "file":
+		dir, err := pokecache.DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		if backend == "file" {
+			return pokecache.NewFileStore(dir, pokecache.WithFileCompression(compress)), nil
+		}
+		options = append(options, pokecache.WithDir(dir))
+		return pokecache.NewCache(pokeapi.DefaultCacheInterval, options...), nil
+	case "memory":
+		return pokecache.NewCache(pokeapi.DefaultCacheInterval, options...), nil
+	case "none":
+		return pokecache.NopStore{}, nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q, expected tiered, memory, file or none", backend)
+	}
+}
+
+// Lowercased copies of the words of input, split by the REPL or the shell
+func cleanInput(words []string) []string {
+	cleaned := make([]string, len(words))
+	for i, word := range words {
 		cleaned[i] = strings.ToLower(word)
 	}
 	return cleaned
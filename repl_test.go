@@ -1,6 +1,12 @@
 package main
 
-import "testing"
+import (
+	"github.com/venzy/pokedexcli/internal/commands"
+	"github.com/venzy/pokedexcli/internal/pokeapi"
+	"slices"
+	"strings"
+	"testing"
+)
 
 func TestCleanInput(t *testing.T) {
 	cases := []struct {
@@ -27,7 +33,8 @@ func TestCleanInput(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		actual := cleanInput(c.input)
+		// Split as the REPL does before running a command
+		actual := cleanInput(strings.Fields(c.input))
 		// Check the length of the actual slice
 		// if they don't match, use t.Errorf to print an error message
 		// and fail the test
@@ -45,4 +52,29 @@ func TestCleanInput(t *testing.T) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestRunCommandArguments(t *testing.T) {
+	client := pokeapi.NewClient()
+	defer client.Close()
+	commandContext := commands.NewContext(client)
+	interrupts := newInterruptHandler()
+
+	for _, tokens := range [][]string{nil, {""}, {" "}} {
+		if err := runCommand(interrupts, commandContext, tokens); err == nil {
+			t.Errorf("%q: expected a usage error", tokens)
+		}
+	}
+
+	// Quoted arguments from the shell must arrive whole
+	err := runCommand(interrupts, commandContext, []string{"nosuchcommand", "Mr Mime", "ja-Hrkt"})
+	if err == nil {
+		t.Errorf("expected an unknown command error")
+	}
+	if !slices.Equal(commandContext.RawArguments, []string{"Mr Mime", "ja-Hrkt"}) {
+		t.Errorf("unexpected raw arguments: %q", commandContext.RawArguments)
+	}
+	if !slices.Equal(commandContext.Arguments, []string{"mr mime", "ja-hrkt"}) {
+		t.Errorf("unexpected arguments: %q", commandContext.Arguments)
+	}
+}